@@ -1,3 +1,8 @@
 module github.com/Friends-Of-Noso/NosoData-Go
 
 go 1.23.1
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0
+	golang.org/x/crypto v0.36.0
+)
@@ -0,0 +1,162 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cOrderTypeCustom = "CUSTOM"
+)
+
+// LegacyAliasRegistration is an alias assigned to an address. In CUSTOM
+// orders the address is the account and the receiver holds the alias.
+type LegacyAliasRegistration struct {
+	Alias   string `json:"alias"`
+	Address string `json:"address"`
+	Block   int64  `json:"block"`
+	OrderID string `json:"order-id,omitempty"`
+}
+
+// LegacyAliasRegistry resolves aliases and addresses in both directions at
+// any block height
+type LegacyAliasRegistry struct {
+	Conflicts []LegacyAliasRegistration `json:"conflicts"` // Registrations that were ignored
+
+	byAlias   map[string]LegacyAliasRegistration
+	byAddress map[string]LegacyAliasRegistration
+}
+
+// NewLegacyAliasRegistry creates an empty registry
+func NewLegacyAliasRegistry() *LegacyAliasRegistry {
+	return &LegacyAliasRegistry{
+		byAlias:   make(map[string]LegacyAliasRegistration),
+		byAddress: make(map[string]LegacyAliasRegistration),
+	}
+}
+
+// BuildAliasRegistry scans the blocks from `from` to `to` for alias registrations
+func BuildAliasRegistry(store *LegacyBlockStore, from, to int64) (*LegacyAliasRegistry, error) {
+	r := NewLegacyAliasRegistry()
+	err := store.Iterate(from, to, func(b *LegacyBlock) error {
+		r.ApplyBlock(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SeedFromSummary registers the aliases of a summary located in the headers,
+// at its block
+func (r *LegacyAliasRegistry) SeedFromSummary(s *LegacySummary) error {
+	if s.Block < 0 {
+		return ErrSummaryBlockUnknown
+	}
+	for i := range s.Accounts {
+		a := &s.Accounts[i]
+		if custom := a.Custom.GetString(); custom != "" {
+			r.Register(LegacyAliasRegistration{
+				Alias:   custom,
+				Address: a.Hash.GetString(),
+				Block:   s.Block,
+			})
+		}
+	}
+	return nil
+}
+
+// ApplyBlock registers the aliases of the CUSTOM orders of a block
+func (r *LegacyAliasRegistry) ApplyBlock(b *LegacyBlock) {
+	for i := range b.Transactions {
+		t := &b.Transactions[i]
+		if t.OrderType.GetString() != cOrderTypeCustom {
+			continue
+		}
+		r.Register(LegacyAliasRegistration{
+			Alias:   t.Receiver.GetString(),
+			Address: t.Address.GetString(),
+			Block:   b.Number,
+			OrderID: t.OrderID.GetString(),
+		})
+	}
+}
+
+// Register records an alias and reports whether it was accepted. An alias
+// cannot move and an address cannot get a second alias, so such registrations
+// are kept in Conflicts instead.
+func (r *LegacyAliasRegistry) Register(reg LegacyAliasRegistration) bool {
+	if existing, ok := r.byAlias[reg.Alias]; ok {
+		if existing.Address == reg.Address {
+			return true
+		}
+		r.Conflicts = append(r.Conflicts, reg)
+		return false
+	}
+	if _, ok := r.byAddress[reg.Address]; ok {
+		r.Conflicts = append(r.Conflicts, reg)
+		return false
+	}
+
+	r.byAlias[reg.Alias] = reg
+	r.byAddress[reg.Address] = reg
+	return true
+}
+
+// AddressOf returns the address owning the alias after the block
+func (r *LegacyAliasRegistry) AddressOf(alias string, block int64) (string, bool) {
+	reg, ok := r.byAlias[alias]
+	if !ok || reg.Block > block {
+		return "", false
+	}
+	return reg.Address, true
+}
+
+// AliasOf returns the alias of the address after the block
+func (r *LegacyAliasRegistry) AliasOf(address string, block int64) (string, bool) {
+	reg, ok := r.byAddress[address]
+	if !ok || reg.Block > block {
+		return "", false
+	}
+	return reg.Alias, true
+}
+
+// Resolve returns the address a receiver refers to at the block: the
+// receiver itself when it is an address or an unknown alias
+func (r *LegacyAliasRegistry) Resolve(receiver string, block int64) string {
+	if utils.IsValidAddress(receiver) {
+		return receiver
+	}
+	if address, ok := r.AddressOf(receiver, block); ok {
+		return address
+	}
+	return receiver
+}
+
+// Registrations returns every registration ordered by block and alias
+func (r *LegacyAliasRegistry) Registrations() []LegacyAliasRegistration {
+	regs := make([]LegacyAliasRegistration, 0, len(r.byAlias))
+	for _, reg := range r.byAlias {
+		regs = append(regs, reg)
+	}
+	sort.Slice(regs, func(i, j int) bool {
+		if regs[i].Block != regs[j].Block {
+			return regs[i].Block < regs[j].Block
+		}
+		return regs[i].Alias < regs[j].Alias
+	})
+	return regs
+}
+
+func (r *LegacyAliasRegistry) AsJSON() string {
+	jsonData, err := json.MarshalIndent(r.Registrations(), "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
@@ -0,0 +1,33 @@
+package legacy
+
+import (
+	"testing"
+)
+
+func TestAliasRegistryConflicts(t *testing.T) {
+	r := NewLegacyAliasRegistry()
+	regs := []struct {
+		reg      LegacyAliasRegistration
+		accepted bool
+	}{
+		{LegacyAliasRegistration{Alias: "payouts", Address: "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", Block: 10}, true},
+		{LegacyAliasRegistration{Alias: "payouts", Address: "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", Block: 12}, true},
+		{LegacyAliasRegistration{Alias: "payouts", Address: "N4DwRSTCFdNNV7JXuNfNYrbjipVauF6", Block: 15}, false},
+		{LegacyAliasRegistration{Alias: "other", Address: "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", Block: 20}, false},
+	}
+	for i, tt := range regs {
+		if accepted := r.Register(tt.reg); accepted != tt.accepted {
+			t.Errorf("registration %d: accepted %v, want %v", i, accepted, tt.accepted)
+		}
+	}
+
+	if len(r.Conflicts) != 2 {
+		t.Errorf("conflicts: got %d, want 2", len(r.Conflicts))
+	}
+	if address, ok := r.AddressOf("payouts", 10); !ok || address != "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9" {
+		t.Errorf("payouts: got %s", address)
+	}
+	if _, ok := r.AddressOf("payouts", 9); ok {
+		t.Error("alias resolved before its registration")
+	}
+}
@@ -0,0 +1,120 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LegacyChangeCause identifies why an account balance changed
+type LegacyChangeCause string
+
+const (
+	ChangeCauseTransferOut LegacyChangeCause = "transfer-out"
+	ChangeCauseTransferIn  LegacyChangeCause = "transfer-in"
+	ChangeCauseFee         LegacyChangeCause = "fee"
+	ChangeCauseMinerReward LegacyChangeCause = "miner-reward"
+	ChangeCauseMinerFees   LegacyChangeCause = "miner-fees"
+	ChangeCausePoSPayout   LegacyChangeCause = "pos-payout"
+	ChangeCausePoSReward   LegacyChangeCause = "pos-reward"
+	ChangeCauseMNPayout    LegacyChangeCause = "mn-payout"
+	ChangeCauseMNReward    LegacyChangeCause = "mn-reward"
+)
+
+// LegacyBalanceChange is a signed change applied to one account by a block
+type LegacyBalanceChange struct {
+	Block   int64             `json:"block"`
+	Address string            `json:"address"`
+	Alias   string            `json:"alias,omitempty"` // Alias the order was sent to, when resolved
+	Amount  int64             `json:"amount"`
+	Cause   LegacyChangeCause `json:"cause"`
+	OrderID string            `json:"order-id,omitempty"`
+}
+
+// BalanceChanges returns every balance change applied by the block.
+// Senders pay the transfer amount and the fee, receivers get the transfer
+// amount, the miner gets the reward plus the fees and funds the PoS and MN
+// rewards out of them.
+// Receivers are reported as they appear in the order, which may be an alias.
+func (b *LegacyBlock) BalanceChanges() []LegacyBalanceChange {
+	var changes []LegacyBalanceChange
+
+	add := func(address string, amount int64, cause LegacyChangeCause, orderID string) {
+		if amount == 0 {
+			return
+		}
+		changes = append(changes, LegacyBalanceChange{
+			Block:   b.Number,
+			Address: address,
+			Amount:  amount,
+			Cause:   cause,
+			OrderID: orderID,
+		})
+	}
+
+	// Transactions
+	for i := range b.Transactions {
+		t := &b.Transactions[i]
+		orderID := t.OrderID.GetString()
+		sender := t.Address.GetString()
+
+		add(sender, -t.AmountFee, ChangeCauseFee, orderID)
+		add(sender, -t.AmountTransfer, ChangeCauseTransferOut, orderID)
+		add(t.Receiver.GetString(), t.AmountTransfer, ChangeCauseTransferIn, orderID)
+	}
+
+	// Miner
+	miner := b.Miner.GetString()
+	add(miner, b.Reward, ChangeCauseMinerReward, "")
+	add(miner, b.Fee, ChangeCauseMinerFees, "")
+
+	// PoS rewards
+	add(miner, -b.ProofOfStakeRewardAmount*int64(len(b.ProofOfStakeRewardAddresses)), ChangeCausePoSPayout, "")
+	for i := range b.ProofOfStakeRewardAddresses {
+		add(b.ProofOfStakeRewardAddresses[i].GetString(), b.ProofOfStakeRewardAmount, ChangeCausePoSReward, "")
+	}
+
+	// MN rewards
+	add(miner, -b.MasterNodeRewardAmount*int64(len(b.MasterNodeRewardAddresses)), ChangeCauseMNPayout, "")
+	for i := range b.MasterNodeRewardAddresses {
+		add(b.MasterNodeRewardAddresses[i].GetString(), b.MasterNodeRewardAmount, ChangeCauseMNReward, "")
+	}
+
+	return changes
+}
+
+// ResolvedBalanceChanges returns the block changes with the receivers given
+// as aliases resolved to their addresses
+func (b *LegacyBlock) ResolvedBalanceChanges(r *LegacyAliasRegistry) []LegacyBalanceChange {
+	changes := b.BalanceChanges()
+	for i := range changes {
+		c := &changes[i]
+		if c.Cause != ChangeCauseTransferIn {
+			continue
+		}
+		address := r.Resolve(c.Address, b.Number)
+		if address != c.Address {
+			c.Alias = c.Address
+			c.Address = address
+		}
+	}
+	return changes
+}
+
+// NetBalanceChanges returns the sum of the block changes for every account it touches
+func (b *LegacyBlock) NetBalanceChanges() map[string]int64 {
+	net := make(map[string]int64)
+	for _, c := range b.BalanceChanges() {
+		net[c.Address] += c.Amount
+	}
+	return net
+}
+
+// BalanceChangesAsJSON renders the block changes as JSON
+func (b *LegacyBlock) BalanceChangesAsJSON() string {
+	jsonData, err := json.MarshalIndent(b.BalanceChanges(), "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
@@ -0,0 +1,116 @@
+package legacy
+
+import "testing"
+
+// testBlock returns a block mined by the first test key with the reward and
+// the collected fees
+func testBlock(reward, fee int64) *LegacyBlock {
+	b := &LegacyBlock{
+		Number: 120001,
+		Miner:  *NewPascalShortString(40),
+		Reward: reward,
+		Fee:    fee,
+	}
+	b.Miner.SetString(cTestKeys[0].Address)
+	return b
+}
+
+// testTransfer returns an order line moving amount from sender to receiver
+func testTransfer(sender, receiver string, amount, fee int64) LegacyTransaction {
+	t := NewLegacyTransaction()
+	t.OrderID.SetString("OR1")
+	t.Address.SetString(sender)
+	t.Receiver.SetString(receiver)
+	t.AmountTransfer = amount
+	t.AmountFee = fee
+	return *t
+}
+
+// testAddresses returns short strings holding the addresses
+func testAddresses(addresses ...string) []PascalShortString {
+	s := make([]PascalShortString, len(addresses))
+	for i, a := range addresses {
+		s[i] = *NewPascalShortString(40)
+		s[i].SetString(a)
+	}
+	return s
+}
+
+func TestBlockBalanceChanges(t *testing.T) {
+	miner, sender, receiver := cTestKeys[0].Address, cTestKeys[1].Address, cTestKeys[2].Address
+	pos, mn := cTestKeys[3].Address, cTestKeys[4].Address
+
+	tests := []struct {
+		name   string
+		block  func() *LegacyBlock
+		net    map[string]int64
+		burned int64
+	}{
+		{
+			name:  "reward only",
+			block: func() *LegacyBlock { return testBlock(5000, 0) },
+			net:   map[string]int64{miner: 5000},
+		},
+		{
+			name: "transfer",
+			block: func() *LegacyBlock {
+				b := testBlock(5000, 10)
+				b.Transactions = []LegacyTransaction{testTransfer(sender, receiver, 700, 10)}
+				return b
+			},
+			net: map[string]int64{miner: 5010, sender: -710, receiver: 700},
+		},
+		{
+			name: "two lines to the same receiver",
+			block: func() *LegacyBlock {
+				b := testBlock(5000, 12)
+				b.Transactions = []LegacyTransaction{
+					testTransfer(sender, receiver, 700, 12),
+					testTransfer(pos, receiver, 300, 0),
+				}
+				return b
+			},
+			net: map[string]int64{miner: 5012, sender: -712, pos: -300, receiver: 1000},
+		},
+		{
+			name: "PoS and MN rewards",
+			block: func() *LegacyBlock {
+				b := testBlock(5000, 0)
+				b.ProofOfStakeRewardAmount = 100
+				b.ProofOfStakeRewardAddresses = testAddresses(pos, mn)
+				b.MasterNodeRewardAmount = 250
+				b.MasterNodeRewardAddresses = testAddresses(mn)
+				return b
+			},
+			net: map[string]int64{miner: 4550, pos: 100, mn: 350},
+		},
+		{
+			name: "fees not collected by the miner",
+			block: func() *LegacyBlock {
+				b := testBlock(5000, 0)
+				b.Transactions = []LegacyTransaction{testTransfer(sender, receiver, 700, 10)}
+				return b
+			},
+			net:    map[string]int64{miner: 5000, sender: -710, receiver: 700},
+			burned: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		b := tt.block()
+		net := b.NetBalanceChanges()
+		if len(net) != len(tt.net) {
+			t.Errorf("%s: got %v, want %v", tt.name, net, tt.net)
+		}
+		var sum int64
+		for address, amount := range net {
+			if amount != tt.net[address] {
+				t.Errorf("%s: %s got %d, want %d", tt.name, address, amount, tt.net[address])
+			}
+			sum += amount
+		}
+		if sum != b.Reward-tt.burned {
+			t.Errorf("%s: net sum %d, want reward %d minus burned fees %d", tt.name, sum, b.Reward, tt.burned)
+		}
+	}
+}
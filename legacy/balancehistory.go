@@ -0,0 +1,186 @@
+package legacy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	cDefaultCheckpointInterval int64 = 1000
+)
+
+// errHistoryNotInitialized is returned by a history not created with
+// NewLegacyBalanceHistory
+var errHistoryNotInitialized = errors.New("balance history not initialized, use NewLegacyBalanceHistory")
+
+// balanceCheckpoint is a full copy of the balances after a block
+type balanceCheckpoint struct {
+	block    int64
+	balances map[string]int64
+}
+
+// balanceDelta is the net change of one address in one block
+type balanceDelta struct {
+	block  int64
+	amount int64
+}
+
+// LegacyBalanceHistory answers balance and alias queries at any replayed
+// block height using periodic checkpoints and per-address delta logs
+type LegacyBalanceHistory struct {
+	Interval int64 `json:"interval"`
+	Tip      int64 `json:"tip"` // Last block applied, -1 before genesis
+
+	checkpoints []balanceCheckpoint
+	deltas      map[string][]balanceDelta
+	aliases     *LegacyAliasRegistry
+	current     map[string]int64
+}
+
+// NewLegacyBalanceHistory creates an empty history starting before genesis.
+// A checkpoint is taken every interval blocks; 0 uses the default interval.
+func NewLegacyBalanceHistory(interval int64) *LegacyBalanceHistory {
+	if interval <= 0 {
+		interval = cDefaultCheckpointInterval
+	}
+	h := &LegacyBalanceHistory{
+		Interval: interval,
+		Tip:      -1,
+		deltas:   make(map[string][]balanceDelta),
+		aliases:  NewLegacyAliasRegistry(),
+		current:  make(map[string]int64),
+	}
+	h.checkpoint()
+	return h
+}
+
+// SeedFromSummary starts the history from the state of a summary located in
+// the headers, so only the blocks after it need to be replayed
+func (h *LegacyBalanceHistory) SeedFromSummary(s *LegacySummary) error {
+	if h.Interval <= 0 {
+		return errHistoryNotInitialized
+	}
+	if s.Block < 0 {
+		return ErrSummaryBlockUnknown
+	}
+
+	h.Tip = s.Block
+	h.checkpoints = nil
+	h.deltas = make(map[string][]balanceDelta)
+	h.aliases = NewLegacyAliasRegistry()
+	h.current = make(map[string]int64)
+
+	for i := range s.Accounts {
+		a := &s.Accounts[i]
+		h.current[a.Hash.GetString()] = a.Balance
+	}
+	h.aliases.SeedFromSummary(s)
+	h.checkpoint()
+
+	return nil
+}
+
+// Replay applies the blocks after the current tip up to `to` from the store
+func (h *LegacyBalanceHistory) Replay(store *LegacyBlockStore, to int64) error {
+	return store.Iterate(h.Tip+1, to, h.ApplyBlock)
+}
+
+// ApplyBlock applies the changes of the block following the current tip
+func (h *LegacyBalanceHistory) ApplyBlock(b *LegacyBlock) error {
+	if !h.initialized() {
+		return errHistoryNotInitialized
+	}
+	if b.Number != h.Tip+1 {
+		return fmt.Errorf("expected block %d, got %d", h.Tip+1, b.Number)
+	}
+
+	// Aliases registered in the block
+	h.aliases.ApplyBlock(b)
+
+	// Balances
+	net := make(map[string]int64)
+	for _, c := range b.ResolvedBalanceChanges(h.aliases) {
+		net[c.Address] += c.Amount
+	}
+	for address, amount := range net {
+		if amount == 0 {
+			continue
+		}
+		h.current[address] += amount
+		h.deltas[address] = append(h.deltas[address], balanceDelta{block: b.Number, amount: amount})
+	}
+
+	h.Tip = b.Number
+	if h.Tip%h.Interval == 0 {
+		h.checkpoint()
+	}
+
+	return nil
+}
+
+// BalanceAt returns the balance of the address after the given block
+func (h *LegacyBalanceHistory) BalanceAt(address string, block int64) (int64, error) {
+	if !h.initialized() {
+		return 0, errHistoryNotInitialized
+	}
+	first := h.checkpoints[0].block
+	if block < first || block > h.Tip {
+		return 0, fmt.Errorf("block %d is outside the replayed range %d-%d", block, first, h.Tip)
+	}
+
+	// Latest checkpoint at or before the block
+	i := sort.Search(len(h.checkpoints), func(i int) bool {
+		return h.checkpoints[i].block > block
+	})
+	cp := h.checkpoints[i-1]
+	balance := cp.balances[address]
+
+	// Deltas after the checkpoint up to the block
+	log := h.deltas[address]
+	j := sort.Search(len(log), func(j int) bool {
+		return log[j].block > cp.block
+	})
+	for ; j < len(log) && log[j].block <= block; j++ {
+		balance += log[j].amount
+	}
+
+	return balance, nil
+}
+
+// AliasAt returns the alias of the address after the given block, or an
+// empty string when it had none
+func (h *LegacyBalanceHistory) AliasAt(address string, block int64) string {
+	if h.aliases == nil {
+		return ""
+	}
+	alias, _ := h.aliases.AliasOf(address, block)
+	return alias
+}
+
+// Aliases returns the alias registry built while replaying
+func (h *LegacyBalanceHistory) Aliases() *LegacyAliasRegistry {
+	return h.aliases
+}
+
+// Balances returns a copy of the balances at the tip
+func (h *LegacyBalanceHistory) Balances() map[string]int64 {
+	balances := make(map[string]int64, len(h.current))
+	for address, amount := range h.current {
+		balances[address] = amount
+	}
+	return balances
+}
+
+// initialized reports whether the history was created by its constructor
+func (h *LegacyBalanceHistory) initialized() bool {
+	return h.Interval > 0 && len(h.checkpoints) > 0
+}
+
+// checkpoint stores a copy of the balances at the tip
+func (h *LegacyBalanceHistory) checkpoint() {
+	h.checkpoints = append(h.checkpoints, balanceCheckpoint{
+		block:    h.Tip,
+		balances: h.Balances(),
+	})
+}
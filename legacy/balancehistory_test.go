@@ -0,0 +1,44 @@
+package legacy
+
+import "testing"
+
+func TestBalanceHistoryZeroValue(t *testing.T) {
+	var h LegacyBalanceHistory
+
+	_, err := h.BalanceAt("N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", 0)
+	if err == nil {
+		t.Error("BalanceAt: expected an error on a zero value history")
+	}
+	err = h.ApplyBlock(&LegacyBlock{})
+	if err == nil {
+		t.Error("ApplyBlock: expected an error on a zero value history")
+	}
+	err = h.SeedFromSummary(&LegacySummary{Block: 10})
+	if err == nil {
+		t.Error("SeedFromSummary: expected an error on a zero value history")
+	}
+	if alias := h.AliasAt("N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", 0); alias != "" {
+		t.Errorf("AliasAt: got %q", alias)
+	}
+}
+
+func TestBalanceHistoryApplyBlock(t *testing.T) {
+	h := NewLegacyBalanceHistory(2)
+
+	miner := *NewPascalShortString(40)
+	miner.SetString("N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9")
+	for n := int64(0); n < 3; n++ {
+		err := h.ApplyBlock(&LegacyBlock{Number: n, Miner: miner, Reward: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	balance, err := h.BalanceAt("N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 200 {
+		t.Errorf("balance at 1: got %d, want 200", balance)
+	}
+}
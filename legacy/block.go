@@ -1,15 +1,11 @@
 package legacy
 
 import (
-	"crypto/md5"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"log"
 	"os"
-	"strings"
 
 	"github.com/Friends-Of-Noso/NosoData-Go/utils"
 )
@@ -71,24 +67,16 @@ func (b *LegacyBlock) ReadFromStream(f *os.File) error {
 	}
 
 	// Field HASH
-	// Create a new MD5 hash object
-	hash := md5.New()
-
-	// Copy the file's content into the hash
-	if _, err := io.Copy(hash, f); err != nil {
-		log.Fatal(err)
+	hash, err := hashMD5Stream(f)
+	if err != nil {
+		return err
 	}
-
-	// Get the final hash sum
-	hashInBytes := hash.Sum(nil)
-
-	// Convert the hash to a hexadecimal string and then to uppercase
-	b.HASH = strings.ToUpper(fmt.Sprintf("%x", hashInBytes))
+	b.HASH = hash
 
 	// Seek back to the beginning of the file if you need to process it again
-	_, err := f.Seek(0, 0)
+	_, err = f.Seek(0, 0)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Field Number
@@ -0,0 +1,91 @@
+package legacy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	cBlockExtension = ".blk"
+)
+
+// ErrStopIteration can be returned by an iteration callback to stop early
+var ErrStopIteration = errors.New("stop iteration")
+
+// LegacyBlockStore gives access to the block files in a BLOCKS folder
+type LegacyBlockStore struct {
+	Folder string
+}
+
+// NewLegacyBlockStore creates a block store for the given folder
+func NewLegacyBlockStore(folder string) *LegacyBlockStore {
+	return &LegacyBlockStore{
+		Folder: folder,
+	}
+}
+
+// BlockFilename returns the path of the file for the block number
+func (s *LegacyBlockStore) BlockFilename(n int64) string {
+	return filepath.Join(s.Folder, strconv.FormatInt(n, 10)+cBlockExtension)
+}
+
+// ReadBlock reads the block with the given number
+func (s *LegacyBlockStore) ReadBlock(n int64) (*LegacyBlock, error) {
+	b := &LegacyBlock{}
+	err := b.ReadFromFile(s.BlockFilename(n))
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %s", n, err)
+	}
+	return b, nil
+}
+
+// LastBlock returns the highest block number present in the folder
+func (s *LegacyBlockStore) LastBlock() (int64, error) {
+	entries, err := os.ReadDir(s.Folder)
+	if err != nil {
+		return -1, fmt.Errorf("cannot read folder: %s", err)
+	}
+
+	var last int64 = -1
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, cBlockExtension) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(name, cBlockExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		if n > last {
+			last = n
+		}
+	}
+
+	if last < 0 {
+		return -1, fmt.Errorf("no blocks found in %s", s.Folder)
+	}
+	return last, nil
+}
+
+// Iterate reads the blocks from `from` to `to`, both included, in order and
+// calls fn for each one. Returning ErrStopIteration from fn stops without error.
+func (s *LegacyBlockStore) Iterate(from, to int64, fn func(b *LegacyBlock) error) error {
+	for n := from; n <= to; n++ {
+		b, err := s.ReadBlock(n)
+		if err != nil {
+			return err
+		}
+		err = fn(b)
+		if err == ErrStopIteration {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package legacy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// Keys of the settings known to the config. Keys are matched ignoring case.
+const (
+	cConfigPort         = "Port"
+	cConfigSeedNodes    = "SeedNodes"
+	cConfigMinerAddress = "MinerAddress"
+	cConfigPoolIP       = "PoolIP"
+	cConfigPoolPort     = "PoolPort"
+	cConfigPoolPassword = "PoolPassword"
+	cConfigMNIP         = "MNIP"
+	cConfigMNPort       = "MNPort"
+	cConfigMNSign       = "MNSign"
+	cConfigMNFunds      = "MNFunds"
+
+	cConfigDefaultPort int32 = 8080
+)
+
+var cConfigKeys = []string{
+	cConfigPort,
+	cConfigSeedNodes,
+	cConfigMinerAddress,
+	cConfigPoolIP,
+	cConfigPoolPort,
+	cConfigPoolPassword,
+	cConfigMNIP,
+	cConfigMNPort,
+	cConfigMNSign,
+	cConfigMNFunds,
+}
+
+// LegacyNodeAddress is a node endpoint, written as IP;Port
+type LegacyNodeAddress struct {
+	IP   string `json:"ip"`
+	Port int32  `json:"port"`
+}
+
+// LegacyConfigEntry is a setting the config does not know about
+type LegacyConfigEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// LegacyConfig is the node configuration file: one `Key Value` setting per
+// line. Unknown settings are kept in Extra and written back in place, and
+// keys keep the spelling they were read with.
+type LegacyConfig struct {
+	Port         int32               `json:"port"`
+	SeedNodes    []LegacyNodeAddress `json:"seed-nodes"` // Written as IP;Port:IP;Port
+	MinerAddress string              `json:"miner-address"`
+	PoolIP       string              `json:"pool-ip"`
+	PoolPort     int32               `json:"pool-port"`
+	PoolPassword string              `json:"pool-password" sensitive:"true"`
+	MNIP         string              `json:"mn-ip"`
+	MNPort       int32               `json:"mn-port"`
+	MNSign       string              `json:"mn-sign"`
+	MNFunds      string              `json:"mn-funds"`
+	Extra        []LegacyConfigEntry `json:"extra"`
+
+	order []string // Keys in the order they were read or set
+}
+
+// NewLegacyConfig creates a config with the default port
+func NewLegacyConfig() *LegacyConfig {
+	return &LegacyConfig{
+		Port: cConfigDefaultPort,
+	}
+}
+
+func (c *LegacyConfig) ReadFromFile(f string) error {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return fmt.Errorf("file %s not found", f)
+	}
+
+	file, err := os.Open(f)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %s", err)
+	}
+	defer file.Close()
+
+	return c.ReadFromStream(file)
+}
+
+// ReadFromStream reads the settings from a stream
+func (c *LegacyConfig) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	c.Extra = nil
+	c.order = nil
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(text, " ")
+		value = strings.TrimSpace(value)
+
+		known, err := c.setValue(key, value)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+		if !known {
+			c.Extra = append(c.Extra, LegacyConfigEntry{Key: key, Value: value})
+		}
+		c.order = append(c.order, key)
+	}
+
+	return scanner.Err()
+}
+
+// WriteToFile writes the settings to a file
+func (c *LegacyConfig) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0644, c.WriteToStream)
+}
+
+// WriteToStream writes the settings to a stream, in the order they were read
+// or set. Known settings changed directly on the config follow, and known
+// settings left at their default are not written.
+func (c *LegacyConfig) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	written := make(map[string]bool)
+	extra := 0
+	writeLine := func(key, value string) error {
+		_, err := fmt.Fprintln(w, strings.TrimSpace(key+" "+value))
+		return err
+	}
+
+	for _, key := range c.order {
+		var err error
+		if value, known := c.value(key); known {
+			canonical, _ := configKey(key)
+			if written[canonical] {
+				continue
+			}
+			written[canonical] = true
+			err = writeLine(key, value)
+		} else if extra < len(c.Extra) {
+			err = writeLine(c.Extra[extra].Key, c.Extra[extra].Value)
+			extra++
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	defaults := NewLegacyConfig()
+	for _, key := range cConfigKeys {
+		value, _ := c.value(key)
+		if def, _ := defaults.value(key); written[key] || value == def {
+			continue
+		}
+		err := writeLine(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for ; extra < len(c.Extra); extra++ {
+		err := writeLine(c.Extra[extra].Key, c.Extra[extra].Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns the raw value of a setting, known or not
+func (c *LegacyConfig) Get(key string) (string, bool) {
+	if value, known := c.value(key); known {
+		return value, true
+	}
+	for i := len(c.Extra) - 1; i >= 0; i-- {
+		if strings.EqualFold(c.Extra[i].Key, key) {
+			return c.Extra[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Set changes the raw value of a setting, adding unknown settings to Extra.
+// Settings missing from the file are written after the others.
+func (c *LegacyConfig) Set(key, value string) error {
+	known, err := c.setValue(key, value)
+	if err != nil {
+		return err
+	}
+	if known {
+		if !c.hasKey(key) {
+			c.order = append(c.order, key)
+		}
+		return nil
+	}
+	for i := range c.Extra {
+		if strings.EqualFold(c.Extra[i].Key, key) {
+			c.Extra[i].Value = value
+			return nil
+		}
+	}
+	c.Extra = append(c.Extra, LegacyConfigEntry{Key: key, Value: value})
+	c.order = append(c.order, key)
+	return nil
+}
+
+// Validate checks the ports, IPs and addresses of the settings that are set
+// and returns the problems found
+func (c *LegacyConfig) Validate() []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	checkPort := func(key string, port int32, optional bool) {
+		if optional && port == 0 {
+			return
+		}
+		if port < 1 || port > 65535 {
+			add("%s: invalid port %d", key, port)
+		}
+	}
+	checkIP := func(key, ip string) {
+		if ip != "" && net.ParseIP(ip) == nil {
+			add("%s: invalid IP '%s'", key, ip)
+		}
+	}
+	checkAddress := func(key, address string) {
+		if address != "" && !utils.IsValidAddress(address) {
+			add("%s: invalid address '%s'", key, address)
+		}
+	}
+
+	checkPort(cConfigPort, c.Port, false)
+	for i, n := range c.SeedNodes {
+		checkIP(fmt.Sprintf("%s %d", cConfigSeedNodes, i), n.IP)
+		checkPort(fmt.Sprintf("%s %d", cConfigSeedNodes, i), n.Port, false)
+	}
+	checkAddress(cConfigMinerAddress, c.MinerAddress)
+	checkPort(cConfigPoolPort, c.PoolPort, true)
+	checkIP(cConfigMNIP, c.MNIP)
+	checkPort(cConfigMNPort, c.MNPort, true)
+	checkAddress(cConfigMNSign, c.MNSign)
+	checkAddress(cConfigMNFunds, c.MNFunds)
+
+	return issues
+}
+
+func (c *LegacyConfig) AsJSON() string {
+	return c.AsJSONWithOptions(RenderOptions{})
+}
+
+// AsJSONWithOptions renders the config as JSON, showing the pool password
+// only when the options allow secrets
+func (c *LegacyConfig) AsJSONWithOptions(opts RenderOptions) string {
+	return renderJSON(c, opts)
+}
+
+// hasKey reports whether a known setting was read or set
+func (c *LegacyConfig) hasKey(key string) bool {
+	canonical, _ := configKey(key)
+	for _, k := range c.order {
+		if ck, known := configKey(k); known && ck == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// setValue parses the value of a known setting, and reports false for
+// unknown keys
+func (c *LegacyConfig) setValue(key, value string) (bool, error) {
+	key, known := configKey(key)
+	if !known {
+		return false, nil
+	}
+
+	var err error
+	switch key {
+	case cConfigPort:
+		c.Port, err = parseConfigPort(key, value)
+	case cConfigSeedNodes:
+		c.SeedNodes, err = parseNodeAddresses(value)
+	case cConfigMinerAddress:
+		c.MinerAddress = value
+	case cConfigPoolIP:
+		c.PoolIP = value
+	case cConfigPoolPort:
+		c.PoolPort, err = parseConfigPort(key, value)
+	case cConfigPoolPassword:
+		c.PoolPassword = value
+	case cConfigMNIP:
+		c.MNIP = value
+	case cConfigMNPort:
+		c.MNPort, err = parseConfigPort(key, value)
+	case cConfigMNSign:
+		c.MNSign = value
+	case cConfigMNFunds:
+		c.MNFunds = value
+	}
+	return true, err
+}
+
+// value formats the value of a known setting, and reports false for unknown
+// keys
+func (c *LegacyConfig) value(key string) (string, bool) {
+	key, _ = configKey(key)
+	switch key {
+	case cConfigPort:
+		return strconv.Itoa(int(c.Port)), true
+	case cConfigSeedNodes:
+		return formatNodeAddresses(c.SeedNodes), true
+	case cConfigMinerAddress:
+		return c.MinerAddress, true
+	case cConfigPoolIP:
+		return c.PoolIP, true
+	case cConfigPoolPort:
+		return strconv.Itoa(int(c.PoolPort)), true
+	case cConfigPoolPassword:
+		return c.PoolPassword, true
+	case cConfigMNIP:
+		return c.MNIP, true
+	case cConfigMNPort:
+		return strconv.Itoa(int(c.MNPort)), true
+	case cConfigMNSign:
+		return c.MNSign, true
+	case cConfigMNFunds:
+		return c.MNFunds, true
+	}
+	return "", false
+}
+
+// configKey returns the known key matching key, ignoring case
+func configKey(key string) (string, bool) {
+	for _, k := range cConfigKeys {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return key, false
+}
+
+// String returns the endpoint as written in the config
+func (n LegacyNodeAddress) String() string {
+	return n.IP + cMNPortSeparator + strconv.Itoa(int(n.Port))
+}
+
+func parseConfigPort(key, value string) (int32, error) {
+	if value == "" {
+		return 0, nil
+	}
+	port, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s'", key, value)
+	}
+	return int32(port), nil
+}
+
+// parseNodeAddresses reads a list of IP;Port endpoints separated by colons
+func parseNodeAddresses(value string) ([]LegacyNodeAddress, error) {
+	var nodes []LegacyNodeAddress
+	for _, s := range strings.Split(value, cMNFieldSeparator) {
+		if s == "" {
+			continue
+		}
+		ip, port, ok := strings.Cut(s, cMNPortSeparator)
+		if !ok {
+			return nil, fmt.Errorf("invalid node '%s'", s)
+		}
+		p, err := strconv.ParseInt(port, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port in node '%s'", s)
+		}
+		nodes = append(nodes, LegacyNodeAddress{IP: ip, Port: int32(p)})
+	}
+	return nodes, nil
+}
+
+func formatNodeAddresses(nodes []LegacyNodeAddress) string {
+	s := make([]string, len(nodes))
+	for i := range nodes {
+		s[i] = nodes[i].String()
+	}
+	return strings.Join(s, cMNFieldSeparator)
+}
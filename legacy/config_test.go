@@ -0,0 +1,111 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "nosocfg.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewLegacyConfig()
+	err = c.ReadFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys are matched ignoring case
+	if c.Port != 8080 || c.MinerAddress != "N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa" || len(c.SeedNodes) != 2 {
+		t.Errorf("config: got %+v", c)
+	}
+	if v, ok := c.Get("theme"); !ok || v != "dark" {
+		t.Errorf("theme: got '%s'", v)
+	}
+
+	// Keys keep their spelling and missing keys are not written
+	var out bytes.Buffer
+	err = c.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("written config differs from the original file:\n%s", out.String())
+	}
+}
+
+func TestConfigSet(t *testing.T) {
+	c := NewLegacyConfig()
+	err := c.ReadFromFile(filepath.Join("testdata", "nosocfg.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kv := range [][2]string{{"PORT", "9000"}, {"MNFunds", "N2zAE5YnGLAr7vJa2vpgbNE929ukFCz"}, {"Volume", "3"}} {
+		err = c.Set(kv[0], kv[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c.Set("MNPort", "x") == nil {
+		t.Error("expected an error for an invalid port")
+	}
+
+	var out bytes.Buffer
+	err = c.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "port 9000" {
+		t.Errorf("first line: got '%s'", lines[0])
+	}
+	if tail := lines[len(lines)-2:]; tail[0] != "MNFunds N2zAE5YnGLAr7vJa2vpgbNE929ukFCz" || tail[1] != "Volume 3" {
+		t.Errorf("added settings: got %q", tail)
+	}
+	if len(lines) != 9 {
+		t.Errorf("lines: got %d, want 9", len(lines))
+	}
+}
+
+func TestConfigFromScratch(t *testing.T) {
+	c := NewLegacyConfig()
+	c.MinerAddress = "N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa"
+	c.SeedNodes = []LegacyNodeAddress{{IP: "192.0.2.10", Port: 8080}, {IP: "192.0.2.11", Port: 8081}}
+	err := c.Set("Theme", "dark")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := filepath.Join(t.TempDir(), "nosocfg.psk")
+	err = c.WriteToFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Settings changed directly follow the ones set, and the default port
+	// is not written
+	want := "Theme dark\n" +
+		"SeedNodes 192.0.2.10;8080:192.0.2.11;8081\n" +
+		"MinerAddress N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	read := NewLegacyConfig()
+	err = read.ReadFromFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Port != c.Port || read.MinerAddress != c.MinerAddress || len(read.SeedNodes) != 2 || read.SeedNodes[1] != c.SeedNodes[1] {
+		t.Errorf("config: got %+v", read)
+	}
+}
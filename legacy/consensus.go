@@ -0,0 +1,113 @@
+package legacy
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// ConsensusSnapshot holds the hashes nodes compare to agree on state
+type ConsensusSnapshot struct {
+	SummaryHash string `json:"summary-hash"`
+	GVTHash     string `json:"gvt-hash"`
+	PSOHash     string `json:"pso-hash"`
+	HeadersHash string `json:"headers-hash"`
+}
+
+// ReadFromFiles computes the hashes of the summary, GVT, PSO and headers files
+func (c *ConsensusSnapshot) ReadFromFiles(summary, gvt, pso, headers string) error {
+	var err error
+
+	// Field SummaryHash
+	c.SummaryHash, err = hashMD5File(summary)
+	if err != nil {
+		return err
+	}
+
+	// Field GVTHash
+	c.GVTHash, err = hashMD5File(gvt)
+	if err != nil {
+		return err
+	}
+
+	// Field PSOHash
+	c.PSOHash, err = hashMD5File(pso)
+	if err != nil {
+		return err
+	}
+
+	// Field HeadersHash
+	c.HeadersHash, err = hashMD5File(headers)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Diverged returns the names of the files whose hash differs from the ones
+// in other. Nodes may report shortened hashes, so a shorter value is compared
+// as a prefix, and an empty value is treated as unknown.
+func (c *ConsensusSnapshot) Diverged(other *ConsensusSnapshot) []string {
+	var diverged []string
+
+	check := func(name, local, remote string) {
+		if remote == "" {
+			return
+		}
+		if !strings.HasPrefix(local, strings.ToUpper(remote)) {
+			diverged = append(diverged, name)
+		}
+	}
+
+	check("summary", c.SummaryHash, other.SummaryHash)
+	check("gvt", c.GVTHash, other.GVTHash)
+	check("pso", c.PSOHash, other.PSOHash)
+	check("headers", c.HeadersHash, other.HeadersHash)
+
+	return diverged
+}
+
+func (c *ConsensusSnapshot) AsJSON() string {
+	jsonData, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
+
+// hashMD5File returns the uppercase hexadecimal MD5 of a file
+func hashMD5File(f string) (string, error) {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return "", fmt.Errorf("file %s not found", f)
+	}
+
+	file, err := os.Open(f)
+	if err != nil {
+		return "", fmt.Errorf("cannot open file: %s", err)
+	}
+	defer file.Close()
+
+	return hashMD5Stream(file)
+}
+
+// hashMD5Stream returns the uppercase hexadecimal MD5 of the stream contents
+func hashMD5Stream(r io.Reader) (string, error) {
+	// Create a new MD5 hash object
+	hash := md5.New()
+
+	// Copy the stream's content into the hash
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+
+	// Convert the hash to a hexadecimal string and then to uppercase
+	return strings.ToUpper(fmt.Sprintf("%x", hash.Sum(nil))), nil
+}
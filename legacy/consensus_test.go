@@ -0,0 +1,54 @@
+package legacy
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConsensusReadFromFiles(t *testing.T) {
+	var c ConsensusSnapshot
+	err := c.ReadFromFiles(
+		filepath.Join("testdata", "sumary.psk"),
+		filepath.Join("testdata", "gvts.psk"),
+		filepath.Join("testdata", "psos.dat"),
+		filepath.Join("testdata", "blchhead.nos"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SummaryHash != "190F75EE2C3AD7EA07FE6281DF10530D" {
+		t.Errorf("summary hash: got %s", c.SummaryHash)
+	}
+
+	err = c.ReadFromFiles(filepath.Join("testdata", "missing"), "", "", "")
+	if err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestConsensusDiverged(t *testing.T) {
+	local := ConsensusSnapshot{
+		SummaryHash: "190F75EE2C3AD7EA07FE6281DF10530D",
+		GVTHash:     "0123456789ABCDEF0123456789ABCDEF",
+		PSOHash:     "FEDCBA9876543210FEDCBA9876543210",
+		HeadersHash: "00112233445566778899AABBCCDDEEFF",
+	}
+
+	tests := []struct {
+		name   string
+		remote ConsensusSnapshot
+		want   []string
+	}{
+		{"same", local, nil},
+		{"unknown", ConsensusSnapshot{}, nil},
+		{"prefixes", ConsensusSnapshot{SummaryHash: "190F7", GVTHash: "0123", PSOHash: "fedcba", HeadersHash: "00112233445566778899AABBCCDDEEFF"}, nil},
+		{"summary and headers", ConsensusSnapshot{SummaryHash: "190F8", GVTHash: "0123", HeadersHash: "1"}, []string{"summary", "headers"}},
+		{"longer than the local hash", ConsensusSnapshot{PSOHash: local.PSOHash + "0"}, []string{"pso"}},
+	}
+	for _, tt := range tests {
+		if got := local.Diverged(&tt.remote); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
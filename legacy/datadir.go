@@ -0,0 +1,111 @@
+package legacy
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cDataFolder          = "NOSODATA"
+	cBlocksFolder        = "BLOCKS"
+	cSummaryFilename     = "sumary.psk"
+	cGVTFilename         = "gvts.psk"
+	cPSOFilename         = "psos.dat"
+	cHeadersFilename     = "blchhead.nos"
+	cMasternodesFilename = "masternodes.txt"
+	cConfigFilename      = "nosocfg.psk"
+	cWalletFilename      = "wallet.pkw"
+)
+
+// LegacyDataDir is the layout of a node installation: every data file lives
+// in the NOSODATA folder under Root
+type LegacyDataDir struct {
+	Root string
+}
+
+// NewLegacyDataDir creates a data dir for a node installation. The NOSODATA
+// folder itself is accepted too.
+func NewLegacyDataDir(root string) *LegacyDataDir {
+	root = filepath.Clean(root)
+	if strings.EqualFold(filepath.Base(root), cDataFolder) {
+		root = filepath.Dir(root)
+	}
+	return &LegacyDataDir{
+		Root: root,
+	}
+}
+
+// DataFolder returns the path of the NOSODATA folder
+func (d *LegacyDataDir) DataFolder() string {
+	return filepath.Join(d.Root, cDataFolder)
+}
+
+// BlocksFolder returns the path of the folder holding the block files
+func (d *LegacyDataDir) BlocksFolder() string {
+	return filepath.Join(d.DataFolder(), cBlocksFolder)
+}
+
+func (d *LegacyDataDir) SummaryFilename() string {
+	return filepath.Join(d.DataFolder(), cSummaryFilename)
+}
+
+func (d *LegacyDataDir) GVTFilename() string {
+	return filepath.Join(d.DataFolder(), cGVTFilename)
+}
+
+func (d *LegacyDataDir) PSOFilename() string {
+	return filepath.Join(d.DataFolder(), cPSOFilename)
+}
+
+func (d *LegacyDataDir) HeadersFilename() string {
+	return filepath.Join(d.DataFolder(), cHeadersFilename)
+}
+
+func (d *LegacyDataDir) MasternodesFilename() string {
+	return filepath.Join(d.DataFolder(), cMasternodesFilename)
+}
+
+func (d *LegacyDataDir) ConfigFilename() string {
+	return filepath.Join(d.DataFolder(), cConfigFilename)
+}
+
+func (d *LegacyDataDir) WalletFilename() string {
+	return filepath.Join(d.DataFolder(), cWalletFilename)
+}
+
+// BlockStore returns a block store over the BLOCKS folder
+func (d *LegacyDataDir) BlockStore() *LegacyBlockStore {
+	return NewLegacyBlockStore(d.BlocksFolder())
+}
+
+// ReadConfig reads the node configuration file
+func (d *LegacyDataDir) ReadConfig() (*LegacyConfig, error) {
+	c := NewLegacyConfig()
+	err := c.ReadFromFile(d.ConfigFilename())
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Missing returns the files and folders of the layout that do not exist
+func (d *LegacyDataDir) Missing() []string {
+	var missing []string
+	for _, f := range []string{
+		d.BlocksFolder(),
+		d.SummaryFilename(),
+		d.GVTFilename(),
+		d.PSOFilename(),
+		d.HeadersFilename(),
+		d.MasternodesFilename(),
+		d.ConfigFilename(),
+		d.WalletFilename(),
+	} {
+		if !utils.FileExists(f) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,309 @@
+package legacy
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	cEncryptedWalletMagic   = "NOSOEWLT"
+	cEncryptedWalletVersion = 1
+
+	cScryptLogN    = 15
+	cScryptR       = 8
+	cScryptP       = 1
+	cScryptKeySize = 32
+	cSaltSize      = 16
+)
+
+// ErrWrongPassword is returned when the wallet cannot be decrypted with the password
+var ErrWrongPassword = errors.New("wrong password or corrupted wallet")
+
+// LegacyEncryptedWallet wraps the legacy wallet records with scrypt key
+// derivation and AES-256-GCM authenticated encryption. The header is
+// authenticated along with the records.
+type LegacyEncryptedWallet struct {
+	Version    uint8  `json:"version"`
+	LogN       uint8  `json:"log-n"`
+	R          uint32 `json:"r"`
+	P          uint32 `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"-"`
+
+	wallet *LegacyWallet // Decrypted contents while unlocked
+}
+
+// NewLegacyEncryptedWallet encrypts a wallet with the password
+func NewLegacyEncryptedWallet(w *LegacyWallet, password string) (*LegacyEncryptedWallet, error) {
+	e := &LegacyEncryptedWallet{
+		wallet: w,
+	}
+	err := e.Lock(password)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// IsLocked reports whether the decrypted contents are unavailable
+func (e *LegacyEncryptedWallet) IsLocked() bool {
+	return e.wallet == nil
+}
+
+// Wallet returns the decrypted wallet, or nil while locked
+func (e *LegacyEncryptedWallet) Wallet() *LegacyWallet {
+	return e.wallet
+}
+
+// Lock encrypts the unlocked wallet with the password, using a new salt and
+// nonce, and drops the decrypted contents
+func (e *LegacyEncryptedWallet) Lock(password string) error {
+	if e.wallet == nil {
+		return errors.New("wallet is not unlocked")
+	}
+
+	var plain bytes.Buffer
+	err := e.wallet.WriteToStream(&plain)
+	if err != nil {
+		return err
+	}
+	defer wipe(plain.Bytes())
+
+	e.Version = cEncryptedWalletVersion
+	e.LogN = cScryptLogN
+	e.R = cScryptR
+	e.P = cScryptP
+	e.Salt = make([]byte, cSaltSize)
+	_, err = rand.Read(e.Salt)
+	if err != nil {
+		return err
+	}
+
+	aead, err := e.cipher(password)
+	if err != nil {
+		return err
+	}
+	e.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(e.Nonce)
+	if err != nil {
+		return err
+	}
+
+	e.Ciphertext = aead.Seal(nil, e.Nonce, plain.Bytes(), e.header())
+	e.wallet = nil
+
+	return nil
+}
+
+// Unlock decrypts the wallet with the password
+func (e *LegacyEncryptedWallet) Unlock(password string) (*LegacyWallet, error) {
+	aead, err := e.cipher(password)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+
+	plain, err := aead.Open(nil, e.Nonce, e.Ciphertext, e.header())
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	defer wipe(plain)
+
+	w := &LegacyWallet{}
+	err = w.ReadFromStream(bytes.NewReader(plain))
+	if err != nil {
+		return nil, err
+	}
+	e.wallet = w
+
+	return w, nil
+}
+
+// ChangePassword re-encrypts the wallet with a new password
+func (e *LegacyEncryptedWallet) ChangePassword(oldPassword, newPassword string) error {
+	_, err := e.Unlock(oldPassword)
+	if err != nil {
+		return err
+	}
+	return e.Lock(newPassword)
+}
+
+// ExportToFile decrypts the wallet and writes it in the plain legacy layout,
+// readable by the owner only
+func (e *LegacyEncryptedWallet) ExportToFile(password, f string) error {
+	w, err := e.Unlock(password)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(f, 0600, w.WriteToStream)
+}
+
+func (e *LegacyEncryptedWallet) ReadFromFile(f string) error {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return fmt.Errorf("file %s not found", f)
+	}
+
+	file, err := os.Open(f)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %s", err)
+	}
+	defer file.Close()
+
+	return e.ReadFromStream(file)
+}
+
+// ReadFromStream reads the encrypted container from a stream
+func (e *LegacyEncryptedWallet) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	// Magic
+	magic := make([]byte, len(cEncryptedWalletMagic))
+	_, err := io.ReadFull(r, magic)
+	if err != nil {
+		return err
+	}
+	if string(magic) != cEncryptedWalletMagic {
+		return errors.New("not an encrypted wallet")
+	}
+
+	// Field Version
+	err = binary.Read(r, binary.LittleEndian, &e.Version)
+	if err != nil {
+		return err
+	}
+	if e.Version != cEncryptedWalletVersion {
+		return fmt.Errorf("unsupported encrypted wallet version %d", e.Version)
+	}
+
+	// Fields LogN, R and P
+	err = binary.Read(r, binary.LittleEndian, &e.LogN)
+	if err != nil {
+		return err
+	}
+	err = binary.Read(r, binary.LittleEndian, &e.R)
+	if err != nil {
+		return err
+	}
+	err = binary.Read(r, binary.LittleEndian, &e.P)
+	if err != nil {
+		return err
+	}
+	err = e.checkParams()
+	if err != nil {
+		return err
+	}
+
+	// Fields Salt and Nonce
+	var size uint8
+	for _, field := range []*[]byte{&e.Salt, &e.Nonce} {
+		err = binary.Read(r, binary.LittleEndian, &size)
+		if err != nil {
+			return err
+		}
+		*field = make([]byte, size)
+		_, err = io.ReadFull(r, *field)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Field Ciphertext
+	e.Ciphertext, err = io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	e.wallet = nil
+
+	return nil
+}
+
+// WriteToFile writes the encrypted container to a file, readable by the
+// owner only
+func (e *LegacyEncryptedWallet) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0600, e.WriteToStream)
+}
+
+// WriteToStream writes the encrypted container to a stream
+func (e *LegacyEncryptedWallet) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+	if e.Ciphertext == nil {
+		return errors.New("wallet has not been encrypted")
+	}
+
+	_, err := w.Write(e.header())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(e.Ciphertext)
+	return err
+}
+
+// header returns the encoded header, also used as additional authenticated data
+func (e *LegacyEncryptedWallet) header() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(cEncryptedWalletMagic)
+	binary.Write(&buf, binary.LittleEndian, e.Version)
+	binary.Write(&buf, binary.LittleEndian, e.LogN)
+	binary.Write(&buf, binary.LittleEndian, e.R)
+	binary.Write(&buf, binary.LittleEndian, e.P)
+	buf.WriteByte(byte(len(e.Salt)))
+	buf.Write(e.Salt)
+	buf.WriteByte(byte(len(e.Nonce)))
+	buf.Write(e.Nonce)
+	return buf.Bytes()
+}
+
+// checkParams rejects scrypt parameters other than the ones of the container
+// version, so a crafted file cannot make the key derivation exhaust memory
+func (e *LegacyEncryptedWallet) checkParams() error {
+	if e.LogN != cScryptLogN || e.R != cScryptR || e.P != cScryptP {
+		return fmt.Errorf("unsupported scrypt parameters N=2^%d r=%d p=%d", e.LogN, e.R, e.P)
+	}
+	return nil
+}
+
+// cipher derives the key from the password and returns the AEAD cipher
+func (e *LegacyEncryptedWallet) cipher(password string) (cipher.AEAD, error) {
+	err := e.checkParams()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(password), e.Salt, 1<<e.LogN, int(e.R), int(e.P), cScryptKeySize)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wipe overwrites a buffer holding secrets
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
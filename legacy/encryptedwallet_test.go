@@ -0,0 +1,71 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptedWalletRejectsScryptParameters(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewLegacyEncryptedWallet(&w, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crafted file asking for 2^30 iterations must fail before deriving the key
+	e.LogN = 30
+	var buf bytes.Buffer
+	err = e.WriteToStream(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var crafted LegacyEncryptedWallet
+	err = crafted.ReadFromStream(&buf)
+	if err == nil {
+		t.Fatal("expected an error for unsupported scrypt parameters")
+	}
+	_, err = e.Unlock("secret")
+	if err == nil {
+		t.Fatal("expected Unlock to refuse unsupported scrypt parameters")
+	}
+}
+
+func TestEncryptedWalletExportPermissions(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewLegacyEncryptedWallet(&w, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	encrypted := filepath.Join(dir, "wallet.ewl")
+	err = e.WriteToFile(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported := filepath.Join(dir, "export.pkw")
+	err = e.ExportToFile("secret", exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{encrypted, exported} {
+		info, err := os.Stat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s permissions: got %o, want 600", filepath.Base(f), perm)
+		}
+	}
+}
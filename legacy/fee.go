@@ -0,0 +1,103 @@
+package legacy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// ErrFeeOverpaid is returned for an order paying more than the protocol fee.
+// Nodes accept such orders, the excess goes to the miner, but it is reported
+// since no wallet builds them on purpose.
+var ErrFeeOverpaid = errors.New("fee overpaid")
+
+// VerifyFees checks that the lines of a transfer order pay at least the
+// protocol fee, and that the fee is paid before any amount is moved. An order
+// otherwise valid that pays more returns ErrFeeOverpaid.
+func (o *LegacyOrder) VerifyFees() error {
+	return verifyOrderLineFees(transactionLines(o.Transactions))
+}
+
+// VerifyFees checks the fees of every transfer order in the block and that
+// the block fee is the sum of the fees of its transactions. It returns the
+// problems found, overpaid fees included.
+func (b *LegacyBlock) VerifyFees() []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	var fees int64
+	for i := range b.Transactions {
+		fees += b.Transactions[i].AmountFee
+	}
+	if fees != b.Fee {
+		add("block %d: fee is %s, transactions paid %s", b.Number, utils.ToNoso(b.Fee), utils.ToNoso(fees))
+	}
+
+	for _, o := range groupOrders(b.Transactions) {
+		if o.Transactions[0].OrderType.GetString() != cOrderTypeTransfer {
+			continue
+		}
+		err := o.VerifyFees()
+		if err != nil {
+			add("block %d: order %s: %s", b.Number, o.OrderID, err)
+		}
+	}
+
+	return issues
+}
+
+// verifyOrderLineFees checks the fee of an order split in lines: the fees add
+// up to at least the fee of the total amount, computed once on the whole
+// order, and a line only moves an amount once the lines up to it have paid
+// the whole fee
+func verifyOrderLineFees(lines []orderLine) error {
+	if len(lines) == 0 {
+		return errors.New("order has no lines")
+	}
+
+	var amount, fee int64
+	for _, l := range lines {
+		if l.Amount < 0 || l.Fee < 0 {
+			return fmt.Errorf("negative amount or fee on %s", l.Address)
+		}
+		amount += l.Amount
+		fee += l.Fee
+	}
+
+	expected := utils.GetFee(amount)
+	if fee < expected {
+		return fmt.Errorf("fee underpaid by %s: paid %s, expected %s",
+			utils.ToNoso(expected-fee), utils.ToNoso(fee), utils.ToNoso(expected))
+	}
+
+	var paid int64
+	for i, l := range lines {
+		paid += l.Fee
+		if l.Amount > 0 && paid < expected {
+			return fmt.Errorf("line %d moves an amount before the fee is paid", i+1)
+		}
+	}
+
+	if fee > expected {
+		return fmt.Errorf("%w by %s: paid %s, expected %s", ErrFeeOverpaid,
+			utils.ToNoso(fee-expected), utils.ToNoso(fee), utils.ToNoso(expected))
+	}
+	return nil
+}
+
+// transactionLines returns the amounts and fees of the transactions
+func transactionLines(transactions []LegacyTransaction) []orderLine {
+	lines := make([]orderLine, len(transactions))
+	for i := range transactions {
+		t := &transactions[i]
+		lines[i] = orderLine{
+			Address: t.Address.GetString(),
+			Amount:  t.AmountTransfer,
+			Fee:     t.AmountFee,
+		}
+	}
+	return lines
+}
@@ -0,0 +1,45 @@
+package legacy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyOrderLineFees(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []orderLine
+		ok    bool
+	}{
+		{"exact", []orderLine{{Address: "a", Amount: 100000, Fee: 10}}, true},
+		{"minimum fee", []orderLine{{Address: "a", Amount: 50, Fee: 10}}, true},
+		{"underpaid", []orderLine{{Address: "a", Amount: 200000, Fee: 10}}, false},
+		{"fee paid first", []orderLine{
+			{Address: "a", Amount: 0, Fee: 10},
+			{Address: "b", Amount: 1000, Fee: 0},
+		}, true},
+		{"amount before fee", []orderLine{
+			{Address: "a", Amount: 1000, Fee: 0},
+			{Address: "b", Amount: 0, Fee: 10},
+		}, false},
+	}
+	for _, tt := range tests {
+		err := verifyOrderLineFees(tt.lines)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+	}
+
+	// Overpaid fees are reported once the fee is known to be paid in time
+	err := verifyOrderLineFees([]orderLine{{Address: "a", Amount: 100000, Fee: 20}})
+	if !errors.Is(err, ErrFeeOverpaid) {
+		t.Errorf("overpaid: got %v, want %v", err, ErrFeeOverpaid)
+	}
+	err = verifyOrderLineFees([]orderLine{
+		{Address: "a", Amount: 1000, Fee: 0},
+		{Address: "b", Amount: 0, Fee: 20},
+	})
+	if err == nil || errors.Is(err, ErrFeeOverpaid) {
+		t.Errorf("overpaid late: got %v, want the ordering error", err)
+	}
+}
@@ -28,9 +28,19 @@ func (g *LegacyGVT) ReadFromFile(f string) error {
 	}
 	defer file.Close()
 
+	return g.ReadFromStream(file)
+}
+
+// ReadFromStream reads the GVT entries from a stream
+func (g *LegacyGVT) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
 	for {
 		e := LegacyGVTEntry{}
-		err := e.ReadFromStream(file)
+		err := e.ReadFromStream(r)
 		if err == io.EOF {
 			break
 		}
@@ -45,6 +55,28 @@ func (g *LegacyGVT) ReadFromFile(f string) error {
 	return nil
 }
 
+// WriteToFile writes the GVT entries to a file
+func (g *LegacyGVT) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0644, g.WriteToStream)
+}
+
+// WriteToStream writes the GVT entries to a stream
+func (g *LegacyGVT) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	for i := range g.Entries {
+		err := g.Entries[i].WriteToStream(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (g *LegacyGVT) AsJSON() string {
 	jsonData, err := json.MarshalIndent(g, "", "  ")
 	if err != nil {
@@ -59,9 +91,11 @@ type LegacyGVTEntry struct {
 	Owner   PascalShortString `json:"owner"`  // Capacity 32
 	Hash    PascalShortString `json:"hash"`   // Capacity 64
 	Control int32             `json:"control"`
+
+	stale bool // Owner changed, the hash no longer matches
 }
 
-func (e *LegacyGVTEntry) ReadFromStream(f *os.File) error {
+func (e *LegacyGVTEntry) ReadFromStream(f io.Reader) error {
 	// Check if the stream is nil
 	if f == nil {
 		return errors.New("nil reader provided")
@@ -96,3 +130,25 @@ func (e *LegacyGVTEntry) ReadFromStream(f *os.File) error {
 
 	return nil
 }
+
+// WriteToStream writes the GVT entry record to a stream
+func (e *LegacyGVTEntry) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+	if e.stale {
+		return fmt.Errorf("GVT %s: %w", e.Number.GetString(), ErrGVTHashStale)
+	}
+
+	// Fields Number, Owner and Hash
+	for _, p := range []*PascalShortString{&e.Number, &e.Owner, &e.Hash} {
+		err := p.WriteToStream(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Field Control
+	return binary.Write(w, binary.LittleEndian, e.Control)
+}
@@ -0,0 +1,102 @@
+package legacy
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGVTRoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "gvts.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g LegacyGVT
+	err = g.ReadFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.EntryCount != 3 {
+		t.Fatalf("entries: got %d, want 3", g.EntryCount)
+	}
+
+	var out bytes.Buffer
+	err = g.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Error("written GVT file differs from the original file")
+	}
+}
+
+func TestGVTValidate(t *testing.T) {
+	var g LegacyGVT
+	err := g.ReadFromFile(filepath.Join("testdata", "gvts.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GVT 99 has no owner
+	issues := g.Validate()
+	if len(issues) != 1 {
+		t.Fatalf("issues: got %q, want one", issues)
+	}
+
+	g.Entries[2].SetOwner("N2zAE5YnGLAr7vJa2vpgbNE929ukFCz")
+	g.Entries[1].Number.SetString("1A")
+	g.Entries = append(g.Entries, g.Entries[0])
+	issues = g.Validate()
+	if len(issues) != 2 {
+		t.Errorf("issues: got %q, want an invalid number and a duplicate", issues)
+	}
+	if len(g.OwnedBy("N2zAE5YnGLAr7vJa2vpgbNE929ukFCz")) != 2 {
+		t.Error("owned GVTs not found")
+	}
+}
+
+func TestGVTWriteStaleHash(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "gvts.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := filepath.Join(t.TempDir(), "gvts.psk")
+	err = os.WriteFile(f, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g LegacyGVT
+	err = g.ReadFromFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Setting the same owner keeps the hash valid
+	err = g.Entries[0].SetOwner(g.Entries[0].Owner.GetString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = g.WriteToFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = g.Entries[1].SetOwner("N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = g.WriteToFile(f)
+	if !errors.Is(err, ErrGVTHashStale) {
+		t.Errorf("got %v, want %v", err, ErrGVTHashStale)
+	}
+	written, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Error("refused write changed the file")
+	}
+}
@@ -0,0 +1,130 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	cOrderTypeSendGVT = "SNDGVT"
+)
+
+// LegacyGVTTransfer is one move of a GVT between owners. In SNDGVT orders the
+// reference holds the GVT number, the address the current owner and the
+// receiver the new owner.
+type LegacyGVTTransfer struct {
+	Number  string `json:"number"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Block   int64  `json:"block"`
+	OrderID string `json:"order-id"`
+}
+
+// LegacyGVTHistory holds the chain of owners of every GVT
+type LegacyGVTHistory struct {
+	Transfers map[string][]LegacyGVTTransfer `json:"transfers"` // By GVT number, in block order
+}
+
+// NewLegacyGVTHistory creates an empty history
+func NewLegacyGVTHistory() *LegacyGVTHistory {
+	return &LegacyGVTHistory{
+		Transfers: make(map[string][]LegacyGVTTransfer),
+	}
+}
+
+// BuildGVTHistory scans the blocks from `from` to `to` for GVT transfers
+func BuildGVTHistory(store *LegacyBlockStore, from, to int64) (*LegacyGVTHistory, error) {
+	h := NewLegacyGVTHistory()
+	err := store.Iterate(from, to, func(b *LegacyBlock) error {
+		h.ApplyBlock(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// ApplyBlock records the GVT transfers of a block
+func (h *LegacyGVTHistory) ApplyBlock(b *LegacyBlock) {
+	for i := range b.Transactions {
+		t := &b.Transactions[i]
+		if t.OrderType.GetString() != cOrderTypeSendGVT {
+			continue
+		}
+		number := t.Reference.GetString()
+		h.Transfers[number] = append(h.Transfers[number], LegacyGVTTransfer{
+			Number:  number,
+			From:    t.Address.GetString(),
+			To:      t.Receiver.GetString(),
+			Block:   b.Number,
+			OrderID: t.OrderID.GetString(),
+		})
+	}
+}
+
+// Owners returns the transfers of a GVT in the order they happened
+func (h *LegacyGVTHistory) Owners(number string) []LegacyGVTTransfer {
+	return h.Transfers[number]
+}
+
+// OwnerAt returns the owner of a GVT after the block, and false when no
+// transfer up to that block was recorded
+func (h *LegacyGVTHistory) OwnerAt(number string, block int64) (string, bool) {
+	transfers := h.Transfers[number]
+	i := sort.Search(len(transfers), func(i int) bool {
+		return transfers[i].Block > block
+	})
+	if i == 0 {
+		return "", false
+	}
+	return transfers[i-1].To, true
+}
+
+// CrossCheck compares the history with the current GVT file: every transfer
+// must start from the previous owner, and the last owner must match the file.
+// It returns the problems found.
+func (h *LegacyGVTHistory) CrossCheck(g *LegacyGVT) []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	numbers := make([]string, 0, len(h.Transfers))
+	for number := range h.Transfers {
+		numbers = append(numbers, number)
+	}
+	sort.Strings(numbers)
+
+	for _, number := range numbers {
+		transfers := h.Transfers[number]
+		for i := 1; i < len(transfers); i++ {
+			if transfers[i].From != transfers[i-1].To {
+				add("GVT %s: order %s at block %d sent from %s, owner was %s",
+					number, transfers[i].OrderID, transfers[i].Block, transfers[i].From, transfers[i-1].To)
+			}
+		}
+
+		e := g.Lookup(number)
+		if e == nil {
+			add("GVT %s: transferred but not in the GVT file", number)
+			continue
+		}
+		last := transfers[len(transfers)-1].To
+		if e.Owner.GetString() != last {
+			add("GVT %s: file owner is %s, history owner is %s", number, e.Owner.GetString(), last)
+		}
+	}
+
+	return issues
+}
+
+func (h *LegacyGVTHistory) AsJSON() string {
+	jsonData, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
@@ -0,0 +1,66 @@
+package legacy
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// testGVTBlock returns a block with a SNDGVT order moving the GVT and a
+// transfer order between the same addresses
+func testGVTBlock(number int64, gvt, from, to string) *LegacyBlock {
+	send := testTransfer(from, to, 0, 0)
+	send.OrderType.SetString(cOrderTypeSendGVT)
+	send.Reference.SetString(gvt)
+	transfer := testTransfer(from, to, 700, 10)
+	transfer.OrderType.SetString(cOrderTypeTransfer)
+	transfer.Reference.SetString(gvt)
+	return &LegacyBlock{Number: number, Transactions: []LegacyTransaction{send, transfer}}
+}
+
+func TestGVTHistory(t *testing.T) {
+	first, second, third := cTestKeys[0].Address, cTestKeys[1].Address, cTestKeys[3].Address
+	h := NewLegacyGVTHistory()
+	h.ApplyBlock(testGVTBlock(100, "01", first, second))
+	h.ApplyBlock(testGVTBlock(200, "01", second, third))
+
+	if owners := h.Owners("01"); len(owners) != 2 || owners[0].From != first || owners[1].Block != 200 {
+		t.Fatalf("owners: got %+v", owners)
+	}
+
+	tests := []struct {
+		block int64
+		owner string
+		found bool
+	}{
+		{99, "", false},
+		{100, second, true},
+		{150, second, true},
+		{200, third, true},
+		{300, third, true},
+	}
+	for _, tt := range tests {
+		owner, found := h.OwnerAt("01", tt.block)
+		if owner != tt.owner || found != tt.found {
+			t.Errorf("block %d: got %s %v, want %s %v", tt.block, owner, found, tt.owner, tt.found)
+		}
+	}
+	if _, found := h.OwnerAt("00", 300); found {
+		t.Error("GVT 00 was never transferred")
+	}
+
+	var g LegacyGVT
+	err := g.ReadFromFile(filepath.Join("testdata", "gvts.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := h.CrossCheck(&g); len(issues) != 0 {
+		t.Errorf("cross check: got %q", issues)
+	}
+
+	// A transfer sent by an address that no longer owns the GVT, to an
+	// address the file does not have as owner
+	h.ApplyBlock(testGVTBlock(300, "01", second, first))
+	if issues := h.CrossCheck(&g); len(issues) != 2 {
+		t.Errorf("cross check: got %q, want a broken chain and a file mismatch", issues)
+	}
+}
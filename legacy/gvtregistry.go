@@ -0,0 +1,87 @@
+package legacy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// isGVTNumber reports whether a GVT number has two digits, 00 to 99
+func isGVTNumber(number string) bool {
+	return len(number) == 2 &&
+		number[0] >= '0' && number[0] <= '9' &&
+		number[1] >= '0' && number[1] <= '9'
+}
+
+// ErrGVTHashStale is returned when writing an entry moved to a new owner.
+// The node rule to recompute the entry hash is not known, and a file with a
+// stale hash would not match the one of the other nodes.
+var ErrGVTHashStale = errors.New("GVT hash is stale after an owner change and cannot be recomputed")
+
+// SetOwner moves the GVT to a new owner. The hash cannot be recomputed, so
+// the entry can no longer be written.
+func (e *LegacyGVTEntry) SetOwner(owner string) error {
+	if owner == e.Owner.GetString() {
+		return nil
+	}
+	err := e.Owner.SetString(owner)
+	if err != nil {
+		return err
+	}
+	e.stale = true
+	return nil
+}
+
+// Lookup returns the entry of a GVT number, or nil
+func (g *LegacyGVT) Lookup(number string) *LegacyGVTEntry {
+	for i := range g.Entries {
+		if g.Entries[i].Number.GetString() == number {
+			return &g.Entries[i]
+		}
+	}
+	return nil
+}
+
+// OwnedBy returns the entries owned by the address
+func (g *LegacyGVT) OwnedBy(address string) []LegacyGVTEntry {
+	var owned []LegacyGVTEntry
+	for i := range g.Entries {
+		if g.Entries[i].Owner.GetString() == address {
+			owned = append(owned, g.Entries[i])
+		}
+	}
+	return owned
+}
+
+// Validate checks that the entries have two digit numbers without duplicates
+// and that owners are valid addresses. It returns the problems found.
+func (g *LegacyGVT) Validate() []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	seen := make(map[string]int)
+	for i := range g.Entries {
+		e := &g.Entries[i]
+		number := e.Number.GetString()
+
+		if first, ok := seen[number]; ok {
+			add("position %d: GVT %s already present at position %d", i, number, first)
+		} else {
+			seen[number] = i
+		}
+		if !isGVTNumber(number) {
+			add("position %d: unexpected GVT number '%s'", i, number)
+		}
+		if !utils.IsValidAddress(e.Owner.GetString()) {
+			add("position %d: GVT %s has invalid owner '%s'", i, number, e.Owner.GetString())
+		}
+		if e.Number.Clamped() || e.Owner.Clamped() || e.Hash.Clamped() {
+			add("position %d: GVT %s length byte exceeded the field capacity", i, number)
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,222 @@
+package legacy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// cHeaderRecord is the Pascal declaration of a block header:
+//
+//	ResumenData = packed record
+//	  block     : integer;
+//	  blockhash : string[32];
+//	  SumHash   : string[32];
+//	end;
+var cHeaderRecord = NewPascalRecord(true,
+	PascalField{Name: "Block", Type: PascalInt32Field},
+	PascalField{Name: "BlockHash", Type: PascalShortStringField, Capacity: 32},
+	PascalField{Name: "SummaryHash", Type: PascalShortStringField, Capacity: 32},
+)
+
+type LegacyHeaders struct {
+	HeadersCount int64          `json:"headers-count"`
+	Headers      []LegacyHeader `json:"headers"`
+}
+
+func (h *LegacyHeaders) ReadFromFile(f string) error {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return fmt.Errorf("file %s not found", f)
+	}
+
+	file, err := os.Open(f)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %s", err)
+	}
+	defer file.Close()
+
+	return h.ReadFromStream(file)
+}
+
+// ReadFromStream reads the headers from a stream
+func (h *LegacyHeaders) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	h.HeadersCount = 0
+	h.Headers = nil
+	for {
+		e := LegacyHeader{}
+		err := e.ReadFromStream(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		h.HeadersCount += 1
+		h.Headers = append(h.Headers, e)
+	}
+
+	return nil
+}
+
+// WriteToFile writes the headers to a file
+func (h *LegacyHeaders) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0644, h.WriteToStream)
+}
+
+// WriteToStream writes the headers to a stream
+func (h *LegacyHeaders) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	for i := range h.Headers {
+		err := h.Headers[i].WriteToStream(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Iterate calls fn for every header in order. Returning ErrStopIteration
+// from fn stops without error.
+func (h *LegacyHeaders) Iterate(fn func(e *LegacyHeader) error) error {
+	for i := range h.Headers {
+		err := fn(&h.Headers[i])
+		if err == ErrStopIteration {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the header of a block number, or nil
+func (h *LegacyHeaders) Lookup(block int64) *LegacyHeader {
+	for i := range h.Headers {
+		if int64(h.Headers[i].Block) == block {
+			return &h.Headers[i]
+		}
+	}
+	return nil
+}
+
+// Verify checks that the headers follow each other and that every header
+// hash matches the MD5 of its block file, the same hash LegacyBlock computes,
+// without decoding the blocks. It returns the problems found.
+func (h *LegacyHeaders) Verify(store *LegacyBlockStore) []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	for i := range h.Headers {
+		e := &h.Headers[i]
+		if i > 0 && e.Block != h.Headers[i-1].Block+1 {
+			add("header %d: block %d follows block %d", i, e.Block, h.Headers[i-1].Block)
+		}
+
+		hash, err := hashMD5File(store.BlockFilename(int64(e.Block)))
+		if err != nil {
+			add("block %d: %s", e.Block, err)
+			continue
+		}
+		if !strings.EqualFold(hash, e.BlockHash.GetString()) {
+			add("block %d: header hash %s, file hash %s", e.Block, e.BlockHash.GetString(), hash)
+		}
+	}
+
+	return issues
+}
+
+func (h *LegacyHeaders) AsJSON() string {
+	jsonData, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
+
+type LegacyHeader struct {
+	Block       int32             `json:"block"`
+	BlockHash   PascalShortString `json:"block-hash"`   // Capacity 32
+	SummaryHash PascalShortString `json:"summary-hash"` // Capacity 32
+}
+
+// ReadFromStream reads a header record from a stream
+func (e *LegacyHeader) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	record := cHeaderRecord.NewData()
+	err := record.ReadFromStream(r)
+	if err != nil {
+		return err
+	}
+
+	// Field Block
+	e.Block, err = record.Int32("Block")
+	if err != nil {
+		return err
+	}
+
+	// Field BlockHash
+	e.BlockHash, err = record.ShortString("BlockHash")
+	if err != nil {
+		return err
+	}
+
+	// Field SummaryHash
+	e.SummaryHash, err = record.ShortString("SummaryHash")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteToStream writes the header record to a stream
+func (e *LegacyHeader) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	record := cHeaderRecord.NewData()
+
+	// Field Block
+	err := record.SetInt32("Block", e.Block)
+	if err != nil {
+		return err
+	}
+
+	// Fields BlockHash and SummaryHash
+	err = record.SetShortString("BlockHash", &e.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = record.SetShortString("SummaryHash", &e.SummaryHash)
+	if err != nil {
+		return err
+	}
+
+	return record.WriteToStream(w)
+}
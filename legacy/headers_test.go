@@ -0,0 +1,78 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHeadersRoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "blchhead.nos"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var h LegacyHeaders
+	err = h.ReadFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.HeadersCount != 3 {
+		t.Fatalf("headers: got %d, want 3", h.HeadersCount)
+	}
+	e := h.Lookup(120000)
+	if e == nil || e.SummaryHash.GetString() != "190F75EE2C3AD7EA07FE6281DF10530D" {
+		t.Errorf("header 120000: got %+v", e)
+	}
+
+	var out bytes.Buffer
+	err = h.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Error("written headers differ from the original file")
+	}
+}
+
+func TestHeadersVerify(t *testing.T) {
+	store := NewLegacyBlockStore(t.TempDir())
+	header := func(block int32, blockHash string) LegacyHeader {
+		e := LegacyHeader{
+			Block:       block,
+			BlockHash:   *NewPascalShortString(32),
+			SummaryHash: *NewPascalShortString(32),
+		}
+		e.BlockHash.SetString(blockHash)
+		return e
+	}
+
+	var h LegacyHeaders
+	for n := int32(1); n <= 3; n++ {
+		f := store.BlockFilename(int64(n))
+		err := os.WriteFile(f, []byte{byte(n)}, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := hashMD5File(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Hashes match ignoring case
+		if n == 2 {
+			hash = strings.ToLower(hash)
+		}
+		h.Headers = append(h.Headers, header(n, hash))
+	}
+	if issues := h.Verify(store); len(issues) != 0 {
+		t.Fatalf("issues: got %q", issues)
+	}
+
+	h.Headers[2].BlockHash.SetString(h.Headers[0].BlockHash.GetString())
+	h.Headers = append(h.Headers, header(5, ""))
+	if issues := h.Verify(store); len(issues) != 3 {
+		t.Errorf("issues: got %q, want a hash mismatch, a gap and a missing file", issues)
+	}
+}
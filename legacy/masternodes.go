@@ -0,0 +1,260 @@
+package legacy
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cMNPortSeparator  = ";"
+	cMNFieldSeparator = ":"
+	cMNFieldsCount    = 8 // Fields after the IP
+)
+
+// LegacyMasternodes is the masternode list file: the block it was built at
+// followed by the nodes, separated by spaces. Each node is written as
+// IP;Port:Sign:Fund:First:Last:Total:Validations:Hash
+type LegacyMasternodes struct {
+	Block      int64              `json:"block"`
+	NodesCount int64              `json:"nodes-count"`
+	Nodes      []LegacyMasternode `json:"nodes"`
+}
+
+type LegacyMasternode struct {
+	IP          string `json:"ip"`
+	Port        int32  `json:"port"`
+	Sign        string `json:"sign"` // Address signing the node reports
+	Fund        string `json:"fund"` // Address holding the collateral and getting the rewards
+	First       int64  `json:"first"`
+	Last        int64  `json:"last"`
+	Total       int64  `json:"total"`
+	Validations int32  `json:"validations"`
+	Hash        string `json:"hash"`
+}
+
+func (m *LegacyMasternodes) ReadFromFile(f string) error {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return fmt.Errorf("file %s not found", f)
+	}
+
+	file, err := os.Open(f)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %s", err)
+	}
+	defer file.Close()
+
+	return m.ReadFromStream(file)
+}
+
+// ReadFromStream reads the masternode list from a stream
+func (m *LegacyMasternodes) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+
+	// Field Block
+	if !scanner.Scan() {
+		if scanner.Err() != nil {
+			return scanner.Err()
+		}
+		return io.EOF
+	}
+	block, err := strconv.ParseInt(scanner.Text(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid block number '%s'", scanner.Text())
+	}
+	m.Block = block
+
+	// Field Nodes
+	m.NodesCount = 0
+	m.Nodes = nil
+	for scanner.Scan() {
+		n := LegacyMasternode{}
+		err = n.parse(scanner.Text())
+		if err != nil {
+			return err
+		}
+		m.NodesCount += 1
+		m.Nodes = append(m.Nodes, n)
+	}
+
+	return scanner.Err()
+}
+
+// WriteToFile writes the masternode list to a file
+func (m *LegacyMasternodes) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0644, m.WriteToStream)
+}
+
+// WriteToStream writes the masternode list to a stream
+func (m *LegacyMasternodes) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	fields := []string{strconv.FormatInt(m.Block, 10)}
+	for i := range m.Nodes {
+		fields = append(fields, m.Nodes[i].String())
+	}
+	_, err := fmt.Fprintln(w, strings.Join(fields, " "))
+	return err
+}
+
+// Validate checks the IPs, ports and addresses of the nodes and returns the
+// problems found
+func (m *LegacyMasternodes) Validate() []string {
+	var issues []string
+	endpoints := make(map[string]int)
+	funds := make(map[string]int)
+
+	for i := range m.Nodes {
+		n := &m.Nodes[i]
+		for _, issue := range n.Validate() {
+			issues = append(issues, fmt.Sprintf("node %d: %s", i, issue))
+		}
+
+		endpoint := n.IP + cMNPortSeparator + strconv.Itoa(int(n.Port))
+		if first, ok := endpoints[endpoint]; ok {
+			issues = append(issues, fmt.Sprintf("node %d: %s already listed by node %d", i, endpoint, first))
+		} else {
+			endpoints[endpoint] = i
+		}
+		if first, ok := funds[n.Fund]; ok {
+			issues = append(issues, fmt.Sprintf("node %d: fund address %s already used by node %d", i, n.Fund, first))
+		} else {
+			funds[n.Fund] = i
+		}
+	}
+
+	return issues
+}
+
+// CrossCheckRewards checks that every MN reward address of the block is the
+// fund address of a listed node, and returns the problems found. The list must
+// be the one the block was built from.
+func (m *LegacyMasternodes) CrossCheckRewards(b *LegacyBlock) []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	if int(b.MasterNodeRewardCount) != len(b.MasterNodeRewardAddresses) {
+		add("block %d: reward count %d, addresses %d", b.Number, b.MasterNodeRewardCount, len(b.MasterNodeRewardAddresses))
+	}
+
+	listed := make(map[string]bool)
+	for i := range m.Nodes {
+		listed[m.Nodes[i].Fund] = true
+	}
+
+	rewarded := make(map[string]bool)
+	for i := range b.MasterNodeRewardAddresses {
+		address := b.MasterNodeRewardAddresses[i].GetString()
+		if rewarded[address] {
+			add("block %d: %s rewarded more than once", b.Number, address)
+		}
+		rewarded[address] = true
+		if !listed[address] {
+			add("block %d: %s is not a listed masternode", b.Number, address)
+		}
+	}
+
+	return issues
+}
+
+func (m *LegacyMasternodes) AsJSON() string {
+	jsonData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
+
+// Validate checks the IP, port and addresses of the node
+func (n *LegacyMasternode) Validate() []string {
+	var issues []string
+	if ip := net.ParseIP(n.IP); ip == nil || ip.To4() == nil {
+		issues = append(issues, fmt.Sprintf("invalid IP '%s'", n.IP))
+	}
+	if n.Port < 1 || n.Port > 65535 {
+		issues = append(issues, fmt.Sprintf("invalid port %d", n.Port))
+	}
+	if !utils.IsValidAddress(n.Sign) {
+		issues = append(issues, fmt.Sprintf("invalid sign address '%s'", n.Sign))
+	}
+	if !utils.IsValidAddress(n.Fund) {
+		issues = append(issues, fmt.Sprintf("invalid fund address '%s'", n.Fund))
+	}
+	if n.First > n.Last {
+		issues = append(issues, fmt.Sprintf("first block %d after last block %d", n.First, n.Last))
+	}
+	return issues
+}
+
+// String returns the node as written in the list file
+func (n *LegacyMasternode) String() string {
+	return n.IP + cMNPortSeparator + strings.Join([]string{
+		strconv.Itoa(int(n.Port)),
+		n.Sign,
+		n.Fund,
+		strconv.FormatInt(n.First, 10),
+		strconv.FormatInt(n.Last, 10),
+		strconv.FormatInt(n.Total, 10),
+		strconv.Itoa(int(n.Validations)),
+		n.Hash,
+	}, cMNFieldSeparator)
+}
+
+// parse reads a node as written in the list file
+func (n *LegacyMasternode) parse(s string) error {
+	ip, rest, ok := strings.Cut(s, cMNPortSeparator)
+	if !ok {
+		return fmt.Errorf("invalid masternode '%s'", s)
+	}
+	fields := strings.Split(rest, cMNFieldSeparator)
+	if len(fields) != cMNFieldsCount {
+		return fmt.Errorf("invalid masternode '%s'", s)
+	}
+
+	n.IP = ip
+	n.Sign = fields[1]
+	n.Fund = fields[2]
+	n.Hash = fields[7]
+
+	port, err := strconv.ParseInt(fields[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid port '%s'", fields[0])
+	}
+	n.Port = int32(port)
+
+	for i, v := range []*int64{&n.First, &n.Last, &n.Total} {
+		*v, err = strconv.ParseInt(fields[3+i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number '%s' in masternode %s", fields[3+i], ip)
+		}
+	}
+
+	validations, err := strconv.ParseInt(fields[6], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid validations '%s' in masternode %s", fields[6], ip)
+	}
+	n.Validations = int32(validations)
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package legacy
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cTestMasternodes = "120000 " +
+	"192.168.1.10;8080:N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa:N2zAE5YnGLAr7vJa2vpgbNE929ukFCz:110000:119999:500:12:ABCD " +
+	"192.168.1.11;8080:N2zAE5YnGLAr7vJa2vpgbNE929ukFCz:N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa:115000:119999:100:3:EF01\n"
+
+func TestMasternodesRoundTrip(t *testing.T) {
+	var m LegacyMasternodes
+	err := m.ReadFromStream(strings.NewReader(cTestMasternodes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Block != 120000 || m.NodesCount != 2 {
+		t.Fatalf("got block %d with %d nodes", m.Block, m.NodesCount)
+	}
+	if issues := m.Validate(); len(issues) > 0 {
+		t.Errorf("issues: %q", issues)
+	}
+
+	var out bytes.Buffer
+	err = m.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != cTestMasternodes {
+		t.Errorf("written list differs:\n%s", out.String())
+	}
+
+	f := filepath.Join(t.TempDir(), "masternodes.txt")
+	err = m.WriteToFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read LegacyMasternodes
+	err = read.ReadFromFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Block != m.Block || read.NodesCount != m.NodesCount {
+		t.Errorf("read back block %d with %d nodes", read.Block, read.NodesCount)
+	}
+}
+
+func TestMasternodesCrossCheckRewards(t *testing.T) {
+	var m LegacyMasternodes
+	err := m.ReadFromStream(strings.NewReader(cTestMasternodes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reward := func(address string) PascalShortString {
+		p := NewPascalShortString(32)
+		p.SetString(address)
+		return *p
+	}
+	b := LegacyBlock{
+		Number:                120001,
+		MasterNodeRewardCount: 3,
+		MasterNodeRewardAddresses: []PascalShortString{
+			reward("N2zAE5YnGLAr7vJa2vpgbNE929ukFCz"),
+			reward("N2zAE5YnGLAr7vJa2vpgbNE929ukFCz"),
+			reward("N4DwRSTCFdNNV7JXuNfNYrbjipVauF6"),
+		},
+	}
+
+	// A duplicate and an address that is not listed
+	if issues := m.CrossCheckRewards(&b); len(issues) != 2 {
+		t.Errorf("issues: got %q, want 2", issues)
+	}
+
+	b.MasterNodeRewardCount = 2
+	b.MasterNodeRewardAddresses = b.MasterNodeRewardAddresses[1:2]
+	if issues := m.CrossCheckRewards(&b); len(issues) != 1 {
+		t.Errorf("issues: got %q, want a count mismatch", issues)
+	}
+}
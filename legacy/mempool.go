@@ -0,0 +1,289 @@
+package legacy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// ErrSignatureUnverified is returned for a signature that does not verify
+// while the signature scheme is not verified against orders from a node: the
+// order may be valid and the scheme wrong
+var ErrSignatureUnverified = errors.New("signature unverified")
+
+// LegacyMempoolRejection is an order the mempool refused
+type LegacyMempoolRejection struct {
+	OrderID string `json:"order-id"`
+	Reason  string `json:"reason"`
+}
+
+// LegacyMempoolBalance is the unconfirmed movement of an address
+type LegacyMempoolBalance struct {
+	Incoming int64 `json:"incoming"`
+	Outgoing int64 `json:"outgoing"` // Amounts and fees
+}
+
+// LegacyMempool holds the pending transfer orders that are not yet in a
+// block, validated against a summary
+type LegacyMempool struct {
+	Orders []LegacyOrder `json:"orders"`
+
+	summary *LegacySummary
+	aliases map[string]string // Alias to address, from the summary
+	spends  map[string]int64  // Pending amounts and fees by address
+}
+
+// NewLegacyMempool creates an empty mempool validating against the summary
+func NewLegacyMempool(s *LegacySummary) *LegacyMempool {
+	m := &LegacyMempool{
+		summary: s,
+		aliases: make(map[string]string),
+		spends:  make(map[string]int64),
+	}
+	for i := range s.Accounts {
+		if custom := s.Accounts[i].Custom.GetString(); custom != "" {
+			m.aliases[custom] = s.Accounts[i].Hash.GetString()
+		}
+	}
+	return m
+}
+
+// Add validates an order and adds it to the mempool
+func (m *LegacyMempool) Add(o *LegacyOrder) error {
+	err := m.Validate(o)
+	if err != nil {
+		return err
+	}
+
+	for i := range o.Transactions {
+		t := &o.Transactions[i]
+		m.spends[t.Address.GetString()] += t.AmountTransfer + t.AmountFee
+	}
+	m.Orders = append(m.Orders, *o)
+	return nil
+}
+
+// AddTransactions groups pending transactions by order and adds every order,
+// returning the ones refused
+func (m *LegacyMempool) AddTransactions(transactions []LegacyTransaction) []LegacyMempoolRejection {
+	var rejected []LegacyMempoolRejection
+	for _, o := range groupOrders(transactions) {
+		err := m.Add(&o)
+		if err != nil {
+			rejected = append(rejected, LegacyMempoolRejection{
+				OrderID: o.OrderID,
+				Reason:  err.Error(),
+			})
+		}
+	}
+	return rejected
+}
+
+// Validate checks an order against the summary and the pending orders: the
+// lines must be complete, agree on the receiver, reference and timestamp and
+// be correctly signed, the order ID must match the transfer IDs, the fee must
+// match the amount and every sender must afford its lines on top of its
+// pending spends
+func (m *LegacyMempool) Validate(o *LegacyOrder) error {
+	if len(o.Transactions) == 0 {
+		return errors.New("order has no lines")
+	}
+	if m.Lookup(o.OrderID) != nil {
+		return fmt.Errorf("order %s already pending", o.OrderID)
+	}
+
+	first := &o.Transactions[0]
+	var amount int64
+	spends := make(map[string]int64)
+	transferIDs := make([]string, len(o.Transactions))
+	for i := range o.Transactions {
+		t := &o.Transactions[i]
+		if t.OrderID.GetString() != o.OrderID {
+			return fmt.Errorf("line %d belongs to order %s", t.TransferIndex, t.OrderID.GetString())
+		}
+		if t.Receiver.GetString() != first.Receiver.GetString() ||
+			t.Reference.GetString() != first.Reference.GetString() ||
+			t.TimeStamp != first.TimeStamp {
+			return fmt.Errorf("line %d: receiver, reference or timestamp differs from line 1", t.TransferIndex)
+		}
+		if t.OrderType.GetString() != cOrderTypeTransfer {
+			return fmt.Errorf("line %d: unsupported order type '%s'", t.TransferIndex, t.OrderType.GetString())
+		}
+		if int(t.OrderLinesCount) != len(o.Transactions) || t.TransferIndex != int32(i+1) {
+			return fmt.Errorf("line %d of %d: order has %d lines", t.TransferIndex, t.OrderLinesCount, len(o.Transactions))
+		}
+		if t.AmountTransfer < 0 || t.AmountFee < 0 {
+			return fmt.Errorf("line %d: negative amount", t.TransferIndex)
+		}
+		err := verifyOrderLine(t)
+		if err != nil {
+			return err
+		}
+		amount += t.AmountTransfer
+		spends[t.Address.GetString()] += t.AmountTransfer + t.AmountFee
+		transferIDs[i] = t.TransferID.GetString()
+	}
+
+	if id := orderID(first.TimeStamp, transferIDs); id != o.OrderID {
+		return fmt.Errorf("order ID %s does not match its lines, expected %s", o.OrderID, id)
+	}
+	err := validateOrder(first.Receiver.GetString(), amount, first.Reference.GetString())
+	if err != nil {
+		return err
+	}
+	err = o.VerifyFees()
+	if err != nil && !errors.Is(err, ErrFeeOverpaid) {
+		return err
+	}
+
+	for address, spend := range spends {
+		var balance int64
+		if a := m.summary.AccountByAddress(address); a != nil {
+			balance = a.Balance
+		}
+		if available := balance - m.spends[address]; spend > available {
+			return fmt.Errorf("insufficient funds on %s: %s available, %s needed",
+				address, utils.ToNoso(available), utils.ToNoso(spend))
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the pending order with the ID, or nil
+func (m *LegacyMempool) Lookup(orderID string) *LegacyOrder {
+	for i := range m.Orders {
+		if m.Orders[i].OrderID == orderID {
+			return &m.Orders[i]
+		}
+	}
+	return nil
+}
+
+// Remove drops an order, and reports whether it was pending
+func (m *LegacyMempool) Remove(orderID string) bool {
+	for i := range m.Orders {
+		if m.Orders[i].OrderID != orderID {
+			continue
+		}
+		for _, t := range m.Orders[i].Transactions {
+			m.spends[t.Address.GetString()] -= t.AmountTransfer + t.AmountFee
+		}
+		m.Orders = append(m.Orders[:i], m.Orders[i+1:]...)
+		return true
+	}
+	return false
+}
+
+// ApplyBlock drops the orders confirmed by the block
+func (m *LegacyMempool) ApplyBlock(b *LegacyBlock) {
+	for i := range b.Transactions {
+		m.Remove(b.Transactions[i].OrderID.GetString())
+	}
+}
+
+// Ordered returns the pending lines in the order they go into a block: by
+// timestamp, order ID and line index
+func (m *LegacyMempool) Ordered() []LegacyTransaction {
+	var transactions []LegacyTransaction
+	for i := range m.Orders {
+		transactions = append(transactions, m.Orders[i].Transactions...)
+	}
+	sortTransactions(transactions)
+	return transactions
+}
+
+// Unconfirmed returns the unconfirmed incoming and outgoing amounts of every
+// address with pending orders. Alias receivers are resolved with the summary.
+func (m *LegacyMempool) Unconfirmed() map[string]LegacyMempoolBalance {
+	balances := make(map[string]LegacyMempoolBalance)
+	for i := range m.Orders {
+		for _, t := range m.Orders[i].Transactions {
+			sender := balances[t.Address.GetString()]
+			sender.Outgoing += t.AmountTransfer + t.AmountFee
+			balances[t.Address.GetString()] = sender
+
+			receiver := m.resolve(t.Receiver.GetString())
+			incoming := balances[receiver]
+			incoming.Incoming += t.AmountTransfer
+			balances[receiver] = incoming
+		}
+	}
+	return balances
+}
+
+// UnconfirmedOf returns the unconfirmed amounts of an address
+func (m *LegacyMempool) UnconfirmedOf(address string) LegacyMempoolBalance {
+	return m.Unconfirmed()[address]
+}
+
+func (m *LegacyMempool) AsJSON() string {
+	jsonData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
+
+// resolve returns the address of a receiver alias known to the summary
+func (m *LegacyMempool) resolve(receiver string) string {
+	if address, ok := m.aliases[receiver]; ok {
+		return address
+	}
+	return receiver
+}
+
+// verifyOrderLine checks that the sender public key owns the address and
+// signed the line. Until the scheme is verified against orders from a node, a
+// failed check is reported as ErrSignatureUnverified rather than invalid.
+func verifyOrderLine(t *LegacyTransaction) error {
+	sender := t.Sender.GetString()
+	if utils.AddressFromPublicKey(sender) != t.Address.GetString() {
+		return fmt.Errorf("line %d: public key does not match address %s", t.TransferIndex, t.Address.GetString())
+	}
+	if !utils.VerifyMessage(t.signatureMessage(), t.Signature.GetString(), sender) {
+		if !cOrderSigningVerified {
+			return fmt.Errorf("line %d: %w", t.TransferIndex, ErrSignatureUnverified)
+		}
+		return fmt.Errorf("line %d: invalid signature", t.TransferIndex)
+	}
+	return nil
+}
+
+// groupOrders gathers transactions into orders, in the order their IDs first
+// appear, with the lines sorted by index
+func groupOrders(transactions []LegacyTransaction) []LegacyOrder {
+	var orders []LegacyOrder
+	positions := make(map[string]int)
+	for _, t := range transactions {
+		id := t.OrderID.GetString()
+		p, ok := positions[id]
+		if !ok {
+			p = len(orders)
+			positions[id] = p
+			orders = append(orders, LegacyOrder{OrderID: id, TimeStamp: t.TimeStamp})
+		}
+		orders[p].Transactions = append(orders[p].Transactions, t)
+	}
+	for i := range orders {
+		sortTransactions(orders[i].Transactions)
+	}
+	return orders
+}
+
+func sortTransactions(transactions []LegacyTransaction) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		a, b := &transactions[i], &transactions[j]
+		if a.TimeStamp != b.TimeStamp {
+			return a.TimeStamp < b.TimeStamp
+		}
+		if a.OrderID.GetString() != b.OrderID.GetString() {
+			return a.OrderID.GetString() < b.OrderID.GetString()
+		}
+		return a.TransferIndex < b.TransferIndex
+	})
+}
@@ -0,0 +1,118 @@
+package legacy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testSignedOrder returns a mempool funding a new account and an order from
+// that account, signed line by line
+func testSignedOrder(t *testing.T, receiver string) (*LegacyMempool, *LegacyOrder, *LegacyWalletAccount) {
+	t.Helper()
+
+	a, err := NewLegacyWalletAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := LegacySummaryAccount{
+		Hash:    *NewPascalShortString(40),
+		Custom:  *NewPascalShortString(40),
+		Balance: 100000,
+	}
+	account.Hash.SetString(a.Hash.GetString())
+	m := NewLegacyMempool(&LegacySummary{Block: 120000, Accounts: []LegacySummaryAccount{account}})
+
+	lines := []orderLine{
+		{Address: a.Hash.GetString(), Amount: 600, Fee: 10},
+		{Address: a.Hash.GetString(), Amount: 400, Fee: 0},
+	}
+	o := newLegacyOrder(lines, receiver, "ref", 1700000000, 120000)
+	for i := range o.Transactions {
+		err = signLine(&o.Transactions[i], a.PublicKey.GetString(), a.PrivateKey.GetString())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m, o, a
+}
+
+func TestMempoolAdd(t *testing.T) {
+	m, o, a := testSignedOrder(t, "N2zAE5YnGLAr7vJa2vpgbNE929ukFCz")
+
+	err := m.Add(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := m.UnconfirmedOf(a.Hash.GetString()); b.Outgoing != 1010 {
+		t.Errorf("outgoing: got %d, want 1010", b.Outgoing)
+	}
+	if m.Add(o) == nil {
+		t.Error("expected an error adding the order twice")
+	}
+}
+
+func TestMempoolValidateLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(o *LegacyOrder)
+	}{
+		{"receiver", func(o *LegacyOrder) {
+			o.Transactions[1].Receiver.SetString("N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa")
+		}},
+		{"reference", func(o *LegacyOrder) {
+			o.Transactions[1].Reference.SetString("other")
+		}},
+		{"timestamp", func(o *LegacyOrder) {
+			o.Transactions[1].TimeStamp++
+		}},
+		{"order ID", func(o *LegacyOrder) {
+			o.OrderID = orderID(o.TimeStamp, []string{"a", "b"})
+			for i := range o.Transactions {
+				o.Transactions[i].OrderID.SetString(o.OrderID)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		m, o, _ := testSignedOrder(t, "N2zAE5YnGLAr7vJa2vpgbNE929ukFCz")
+		tt.change(o)
+		if m.Validate(o) == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestMempoolSignatureUnverified(t *testing.T) {
+	if cOrderSigningVerified {
+		t.Skip("order signing is verified")
+	}
+
+	m, o, _ := testSignedOrder(t, cTestKeys[3].Address)
+	o.Transactions[0].Signature = o.Transactions[1].Signature
+	err := m.Validate(o)
+	if !errors.Is(err, ErrSignatureUnverified) {
+		t.Errorf("got %v, want %v", err, ErrSignatureUnverified)
+	}
+}
+
+func TestMempoolAddOverpaid(t *testing.T) {
+	m, o, a := testSignedOrder(t, cTestKeys[3].Address)
+	o.Transactions[0].AmountFee = 30
+	err := signLine(&o.Transactions[0], a.PublicKey.GetString(), a.PrivateKey.GetString())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Add(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := m.UnconfirmedOf(a.Hash.GetString()); b.Outgoing != 1030 {
+		t.Errorf("outgoing: got %d, want 1030", b.Outgoing)
+	}
+
+	block := LegacyBlock{Number: 120001, Fee: 30, Transactions: o.Transactions}
+	if issues := block.VerifyFees(); len(issues) != 1 || !strings.Contains(issues[0], "fee overpaid by") {
+		t.Errorf("block issues: got %q", issues)
+	}
+}
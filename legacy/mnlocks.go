@@ -0,0 +1,94 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// LegacyMNLockPeriod is the span of blocks during which an address is locked.
+// The file does not record when a lock was created, so Start is the PSO
+// snapshot block, the first block the lock is known to be in force.
+type LegacyMNLockPeriod struct {
+	Address string `json:"address"`
+	Start   int64  `json:"start"`
+	Expiry  int64  `json:"expiry"` // First block the address is no longer locked
+}
+
+// LockedAt reports whether the period covers the block
+func (l *LegacyMNLockPeriod) LockedAt(block int64) bool {
+	return block >= l.Start && block < l.Expiry
+}
+
+// ExpiresAt returns the block at which the lock ends. Unlike the PSO items,
+// Expire counts blocks from the PSO snapshot block.
+func (m *LegacyMNLockItem) ExpiresAt(snapshot int32) int64 {
+	return int64(snapshot) + int64(m.Expire)
+}
+
+// Timeline returns the lock period of every MN lock, ordered by expiry
+func (p *LegacyPSO) Timeline() []LegacyMNLockPeriod {
+	periods := make([]LegacyMNLockPeriod, 0, len(p.MNLocks))
+	for i := range p.MNLocks {
+		m := &p.MNLocks[i]
+		periods = append(periods, LegacyMNLockPeriod{
+			Address: m.Address.GetString(),
+			Start:   int64(p.Block),
+			Expiry:  m.ExpiresAt(p.Block),
+		})
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].Expiry < periods[j].Expiry
+	})
+	return periods
+}
+
+// IsLocked reports whether the address is locked at the block. Blocks before
+// the PSO snapshot are unknown and return an error.
+func (p *LegacyPSO) IsLocked(address string, block int64) (bool, error) {
+	if block < int64(p.Block) {
+		return false, fmt.Errorf("block %d is before the PSO snapshot block %d", block, p.Block)
+	}
+	for _, l := range p.Timeline() {
+		if l.Address == address && l.LockedAt(block) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UnlockBlock returns the block at which the address is no longer locked,
+// and false when it has no lock
+func (p *LegacyPSO) UnlockBlock(address string) (int64, bool) {
+	var unlock int64
+	found := false
+	for _, l := range p.Timeline() {
+		if l.Address == address && l.Expiry > unlock {
+			unlock = l.Expiry
+			found = true
+		}
+	}
+	return unlock, found
+}
+
+// ExpiringWithin returns the locks that expire from block `from` to
+// `from+window`, excluding the latter, ordered by expiry
+func (p *LegacyPSO) ExpiringWithin(from, window int64) []LegacyMNLockPeriod {
+	var expiring []LegacyMNLockPeriod
+	for _, l := range p.Timeline() {
+		if l.Expiry >= from && l.Expiry < from+window {
+			expiring = append(expiring, l)
+		}
+	}
+	return expiring
+}
+
+// TimelineAsJSON renders the MN lock timeline as JSON
+func (p *LegacyPSO) TimelineAsJSON() string {
+	jsonData, err := json.MarshalIndent(p.Timeline(), "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
@@ -0,0 +1,49 @@
+package legacy
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPSOIsLocked(t *testing.T) {
+	var p LegacyPSO
+	err := p.ReadFromFile(filepath.Join("testdata", "psos.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const address = "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9"
+	tests := []struct {
+		block  int64
+		locked bool
+	}{
+		{120000, true},
+		{120499, true},
+		{120500, false},
+	}
+	for _, tt := range tests {
+		locked, err := p.IsLocked(address, tt.block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked != tt.locked {
+			t.Errorf("block %d: locked %v, want %v", tt.block, locked, tt.locked)
+		}
+	}
+
+	_, err = p.IsLocked(address, 119999)
+	if err == nil {
+		t.Error("expected an error before the snapshot block")
+	}
+
+	// Expire is 500 blocks after the snapshot block
+	unlock, ok := p.UnlockBlock(address)
+	if !ok || unlock != 120500 {
+		t.Errorf("unlock: got %d, want 120500", unlock)
+	}
+
+	expiring := p.ExpiringWithin(120600, 500)
+	if len(expiring) != 1 || expiring[0].Address != "N4DwRSTCFdNNV7JXuNfNYrbjipVauF6" || expiring[0].Expiry != 121000 {
+		t.Errorf("expiring: got %+v", expiring)
+	}
+}
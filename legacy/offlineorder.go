@@ -0,0 +1,281 @@
+package legacy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cUnsignedOrderFormat = "noso-unsigned-order"
+	cSignedOrderFormat   = "noso-signed-order"
+	cOfflineOrderVersion = 1
+)
+
+// LegacyOrderInput is the part of an unsigned order paid by one address,
+// with the balance it had in the summary the order was built against
+type LegacyOrderInput struct {
+	Address string `json:"address"`
+	Balance int64  `json:"balance"`
+	Amount  int64  `json:"amount"`
+	Fee     int64  `json:"fee"`
+}
+
+// LegacyUnsignedOrder describes a transfer order built on an online machine
+// from summary balances, to be signed on a machine holding the wallet
+type LegacyUnsignedOrder struct {
+	Format       string             `json:"format"`
+	Version      int                `json:"version"`
+	SummaryBlock int64              `json:"summary-block"`
+	TimeStamp    int64              `json:"timestamp"`
+	Receiver     string             `json:"receiver"`
+	Amount       int64              `json:"amount"`
+	Fee          int64              `json:"fee"`
+	Reference    string             `json:"reference"`
+	Inputs       []LegacyOrderInput `json:"inputs"`
+}
+
+// NewLegacyUnsignedOrder plans an order paying amount to receiver from the
+// given addresses, using their balances in a summary located in the headers
+func NewLegacyUnsignedOrder(s *LegacySummary, addresses []string, receiver string, amount int64, reference string) (*LegacyUnsignedOrder, error) {
+	if s.Block < 0 {
+		return nil, ErrSummaryBlockUnknown
+	}
+	err := validateOrder(receiver, amount, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]int64, len(addresses))
+	for _, address := range addresses {
+		a := s.AccountByAddress(address)
+		if a == nil {
+			return nil, fmt.Errorf("address %s not in the summary", address)
+		}
+		available[address] = a.Balance
+	}
+
+	lines, err := planOrderLines(addresses, available, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &LegacyUnsignedOrder{
+		Format:       cUnsignedOrderFormat,
+		Version:      cOfflineOrderVersion,
+		SummaryBlock: s.Block,
+		TimeStamp:    time.Now().Unix(),
+		Receiver:     receiver,
+		Amount:       amount,
+		Fee:          utils.GetFee(amount),
+		Reference:    reference,
+	}
+	for _, l := range lines {
+		u.Inputs = append(u.Inputs, LegacyOrderInput{
+			Address: l.Address,
+			Balance: available[l.Address],
+			Amount:  l.Amount,
+			Fee:     l.Fee,
+		})
+	}
+
+	return u, nil
+}
+
+// Validate checks that the order is consistent: format, fee, and inputs
+// covering exactly the amount and fee within their balances
+func (u *LegacyUnsignedOrder) Validate() error {
+	if u.Format != cUnsignedOrderFormat || u.Version != cOfflineOrderVersion {
+		return fmt.Errorf("unsupported order format %s version %d", u.Format, u.Version)
+	}
+	err := validateOrder(u.Receiver, u.Amount, u.Reference)
+	if err != nil {
+		return err
+	}
+	if u.Fee != utils.GetFee(u.Amount) {
+		return fmt.Errorf("fee %d does not match the protocol fee %d", u.Fee, utils.GetFee(u.Amount))
+	}
+	if len(u.Inputs) == 0 {
+		return errors.New("order has no inputs")
+	}
+
+	var amount, fee int64
+	seen := make(map[string]bool)
+	for _, in := range u.Inputs {
+		if seen[in.Address] {
+			return fmt.Errorf("address %s used twice", in.Address)
+		}
+		seen[in.Address] = true
+		if !utils.IsValidAddress(in.Address) {
+			return fmt.Errorf("invalid input address %s", in.Address)
+		}
+		if in.Amount < 0 || in.Fee < 0 {
+			return fmt.Errorf("negative amount in input %s", in.Address)
+		}
+		if in.Amount+in.Fee > in.Balance {
+			return fmt.Errorf("input %s spends more than its balance", in.Address)
+		}
+		amount += in.Amount
+		fee += in.Fee
+	}
+	if amount != u.Amount || fee != u.Fee {
+		return errors.New("inputs do not add up to the amount and fee")
+	}
+
+	lines := make([]orderLine, len(u.Inputs))
+	for i, in := range u.Inputs {
+		lines[i] = orderLine{Address: in.Address, Amount: in.Amount, Fee: in.Fee}
+	}
+	return verifyOrderLineFees(lines)
+}
+
+// Sign validates the order and signs every input with the wallet keys. It
+// returns ErrOrderSigningUnverified until the signature scheme is verified.
+func (u *LegacyUnsignedOrder) Sign(w *LegacyWallet) (*LegacySignedOrder, error) {
+	if !cOrderSigningVerified {
+		return nil, ErrOrderSigningUnverified
+	}
+	return u.sign(w)
+}
+
+// sign validates the order and signs every input with the wallet keys
+func (u *LegacyUnsignedOrder) sign(w *LegacyWallet) (*LegacySignedOrder, error) {
+	err := u.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	order := u.order()
+	signed := &LegacySignedOrder{
+		Format:   cSignedOrderFormat,
+		Version:  cOfflineOrderVersion,
+		Unsigned: *u,
+	}
+	for i := range order.Transactions {
+		t := &order.Transactions[i]
+		address := t.Address.GetString()
+		n := w.IndexOf(address)
+		if n < 0 {
+			return nil, fmt.Errorf("address %s not in the wallet", address)
+		}
+		a := &w.Accounts[n]
+		err = signLine(t, a.PublicKey.GetString(), a.PrivateKey.GetString())
+		if err != nil {
+			return nil, err
+		}
+		signed.Signatures = append(signed.Signatures, LegacyOrderSignature{
+			PublicKey: t.Sender.GetString(),
+			Signature: t.Signature.GetString(),
+		})
+	}
+
+	return signed, nil
+}
+
+// order creates the unsigned transactions described by the inputs
+func (u *LegacyUnsignedOrder) order() *LegacyOrder {
+	lines := make([]orderLine, len(u.Inputs))
+	for i, in := range u.Inputs {
+		lines[i] = orderLine{Address: in.Address, Amount: in.Amount, Fee: in.Fee}
+	}
+	return newLegacyOrder(lines, u.Receiver, u.Reference, u.TimeStamp, u.SummaryBlock)
+}
+
+func (u *LegacyUnsignedOrder) ReadFromFile(f string) error {
+	return readJSONFile(f, u)
+}
+
+func (u *LegacyUnsignedOrder) WriteToFile(f string) error {
+	return writeJSONFile(f, u)
+}
+
+// LegacyOrderSignature holds the sender key and signature of one input
+type LegacyOrderSignature struct {
+	PublicKey string `json:"public-key"`
+	Signature string `json:"signature"`
+}
+
+// LegacySignedOrder is an unsigned order with the signatures of its inputs,
+// ready to be finalized on an online machine
+type LegacySignedOrder struct {
+	Format     string                 `json:"format"`
+	Version    int                    `json:"version"`
+	Unsigned   LegacyUnsignedOrder    `json:"unsigned"`
+	Signatures []LegacyOrderSignature `json:"signatures"`
+}
+
+// Finalize validates the signed order and returns it ready for broadcast.
+// When a summary is given, the inputs are checked against its balances.
+func (s *LegacySignedOrder) Finalize(summary *LegacySummary) (*LegacyOrder, error) {
+	if s.Format != cSignedOrderFormat || s.Version != cOfflineOrderVersion {
+		return nil, fmt.Errorf("unsupported order format %s version %d", s.Format, s.Version)
+	}
+	err := s.Unsigned.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Signatures) != len(s.Unsigned.Inputs) {
+		return nil, fmt.Errorf("expected %d signatures, got %d", len(s.Unsigned.Inputs), len(s.Signatures))
+	}
+
+	order := s.Unsigned.order()
+	for i := range order.Transactions {
+		t := &order.Transactions[i]
+		sig := s.Signatures[i]
+		address := t.Address.GetString()
+
+		t.Sender.SetString(sig.PublicKey)
+		t.Signature.SetString(sig.Signature)
+		err = verifyOrderLine(t)
+		if err != nil {
+			return nil, err
+		}
+
+		if summary != nil {
+			a := summary.AccountByAddress(address)
+			if a == nil {
+				return nil, fmt.Errorf("address %s not in the summary", address)
+			}
+			if a.Balance < t.AmountTransfer+t.AmountFee {
+				return nil, fmt.Errorf("address %s no longer has enough funds", address)
+			}
+		}
+	}
+
+	return order, nil
+}
+
+func (s *LegacySignedOrder) ReadFromFile(f string) error {
+	return readJSONFile(f, s)
+}
+
+func (s *LegacySignedOrder) WriteToFile(f string) error {
+	return writeJSONFile(f, s)
+}
+
+// readJSONFile decodes a JSON file into v
+func readJSONFile(f string, v any) error {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return fmt.Errorf("file %s not found", f)
+	}
+
+	data, err := os.ReadFile(f)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %s", err)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile encodes v as indented JSON into a file
+func writeJSONFile(f string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f, data, 0600)
+}
@@ -0,0 +1,135 @@
+package legacy
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// testSignedOfflineOrder plans an order from the testdata wallet addresses,
+// writes and reads it back, then signs it and writes and reads it back again
+func testSignedOfflineOrder(t *testing.T) (*LegacySummary, *LegacySignedOrder) {
+	t.Helper()
+
+	var s LegacySummary
+	err := s.ReadFromFile(filepath.Join("testdata", "sumary.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Block = 120000
+	var w LegacyWallet
+	err = w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses := []string{cTestKeys[0].Address, cTestKeys[1].Address}
+	u, err := NewLegacyUnsignedOrder(&s, addresses, cTestKeys[2].Address, 1000000, "rent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedFile := filepath.Join(t.TempDir(), "order.json")
+	err = u.WriteToFile(unsignedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read LegacyUnsignedOrder
+	err = read.ReadFromFile(unsignedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := read.sign(&w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedFile := filepath.Join(t.TempDir(), "signed.json")
+	err = signed.WriteToFile(signedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var readSigned LegacySignedOrder
+	err = readSigned.ReadFromFile(signedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &s, &readSigned
+}
+
+func TestOfflineOrderFinalize(t *testing.T) {
+	s, signed := testSignedOfflineOrder(t)
+
+	o, err := signed.Finalize(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var amount, fee int64
+	for i := range o.Transactions {
+		amount += o.Transactions[i].AmountTransfer
+		fee += o.Transactions[i].AmountFee
+	}
+	if amount != 1000000 || fee != 100 {
+		t.Errorf("got amount %d and fee %d, want 1000000 and 100", amount, fee)
+	}
+	err = NewLegacyMempool(s).Add(o)
+	if err != nil {
+		t.Errorf("mempool: %v", err)
+	}
+}
+
+func TestOfflineOrderTamperedAmount(t *testing.T) {
+	s, signed := testSignedOfflineOrder(t)
+
+	// The fee of 1000001 is still 100, so only the signature can catch it
+	n := len(signed.Unsigned.Inputs) - 1
+	signed.Unsigned.Inputs[n].Amount++
+	signed.Unsigned.Amount++
+	_, err := signed.Finalize(s)
+	if !errors.Is(err, ErrSignatureUnverified) {
+		t.Errorf("got %v, want %v", err, ErrSignatureUnverified)
+	}
+}
+
+func TestOfflineOrderWrongKey(t *testing.T) {
+	s, signed := testSignedOfflineOrder(t)
+
+	signed.Signatures[0].PublicKey = cTestKeys[2].PublicKey
+	if _, err := signed.Finalize(s); err == nil {
+		t.Error("expected an error with the public key of another address")
+	}
+
+	s, signed = testSignedOfflineOrder(t)
+	message := signed.Unsigned.order().Transactions[0].signatureMessage()
+	signature, err := utils.SignMessage(message, cTestKeys[2].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed.Signatures[0].Signature = signature
+	_, err = signed.Finalize(s)
+	if !errors.Is(err, ErrSignatureUnverified) {
+		t.Errorf("got %v, want %v", err, ErrSignatureUnverified)
+	}
+}
+
+func TestOfflineOrderSignUnverified(t *testing.T) {
+	if cOrderSigningVerified {
+		t.Skip("order signing is verified")
+	}
+
+	var s LegacySummary
+	err := s.ReadFromFile(filepath.Join("testdata", "sumary.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Block = 120000
+	u, err := NewLegacyUnsignedOrder(&s, []string{cTestKeys[1].Address}, cTestKeys[2].Address, 1000, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = u.Sign(&LegacyWallet{})
+	if !errors.Is(err, ErrOrderSigningUnverified) {
+		t.Errorf("got %v, want %v", err, ErrOrderSigningUnverified)
+	}
+}
@@ -0,0 +1,264 @@
+package legacy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cOrderTypeTransfer = "TRFR"
+
+	// cOrderSigningVerified enables signing orders. Set it once
+	// TestNodeBlockOrders passes against a block captured from a node; no
+	// such block is in testdata yet, so the scheme is unconfirmed.
+	cOrderSigningVerified = false
+
+	cProtocolVersion   = 2
+	cProgramVersion    = "0.4.4"
+	cMaxReferenceChars = 64
+)
+
+// ErrOrderSigningUnverified is returned when signing an order while the
+// signature scheme is not verified against orders from a node
+var ErrOrderSigningUnverified = errors.New("order signing is disabled until verified against a node")
+
+// LegacyOrder is a signed order made of one or more transfer lines
+type LegacyOrder struct {
+	OrderID      string              `json:"order-id"`
+	TimeStamp    int64               `json:"timestamp"`
+	Transactions []LegacyTransaction `json:"transactions"`
+}
+
+// AsProtocolString returns the order as the line sent to the nodes
+func (o *LegacyOrder) AsProtocolString() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("NSLORDER %d %s %d ORDER %d",
+		cProtocolVersion, cProgramVersion, o.TimeStamp, len(o.Transactions)))
+	for i := range o.Transactions {
+		sb.WriteString(" $")
+		sb.WriteString(o.Transactions[i].AsProtocolString())
+	}
+	return sb.String()
+}
+
+func (o *LegacyOrder) AsJSON() string {
+	jsonData, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
+
+// LegacyOrderBuilder builds and signs a transfer order paid from one or more
+// wallet accounts, in the order they are given
+type LegacyOrderBuilder struct {
+	Sources   []LegacyWalletAccount
+	Receiver  string // Address or alias
+	Amount    int64
+	Reference string
+	TimeStamp int64 // Unix time of the order, 0 uses the current time
+	LastBlock int64 // Last block known to the sender, part of the transfer IDs
+}
+
+// NewLegacyOrderBuilder creates a builder paying amount to receiver from the sources
+func NewLegacyOrderBuilder(receiver string, amount int64, reference string, sources ...LegacyWalletAccount) *LegacyOrderBuilder {
+	return &LegacyOrderBuilder{
+		Sources:   sources,
+		Receiver:  receiver,
+		Amount:    amount,
+		Reference: reference,
+	}
+}
+
+// orderLine is the part of an order paid by one source
+type orderLine struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+	Fee     int64  `json:"fee"`
+}
+
+// Build computes the fee, splits the payment across the sources and signs
+// every line
+func (b *LegacyOrderBuilder) Build() (*LegacyOrder, error) {
+	err := validateOrder(b.Receiver, b.Amount, b.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	// Funds available on every source
+	available := make(map[string]int64, len(b.Sources))
+	keys := make(map[string]*LegacyWalletAccount, len(b.Sources))
+	var addresses []string
+	for i := range b.Sources {
+		a := &b.Sources[i]
+		address := a.Hash.GetString()
+		if _, ok := keys[address]; ok {
+			continue
+		}
+		keys[address] = a
+		available[address] = a.Balance - a.Pending
+		addresses = append(addresses, address)
+	}
+
+	lines, err := planOrderLines(addresses, available, b.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	timeStamp := b.TimeStamp
+	if timeStamp == 0 {
+		timeStamp = time.Now().Unix()
+	}
+
+	order := newLegacyOrder(lines, b.Receiver, b.Reference, timeStamp, b.LastBlock)
+	for i := range order.Transactions {
+		t := &order.Transactions[i]
+		a := keys[t.Address.GetString()]
+		err = signOrderLine(t, a.PublicKey.GetString(), a.PrivateKey.GetString())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// validateOrder checks the receiver, amount and reference of an order
+func validateOrder(receiver string, amount int64, reference string) error {
+	if utils.LooksLikeAddress(receiver) && !utils.IsValidAddress(receiver) {
+		return fmt.Errorf("invalid receiver '%s': address checksum does not match", receiver)
+	}
+	if !utils.IsValidAddress(receiver) && !utils.IsValidAlias(receiver) {
+		return fmt.Errorf("invalid receiver '%s'", receiver)
+	}
+	if amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	if len(reference) > cMaxReferenceChars || strings.ContainsAny(reference, " $") {
+		return fmt.Errorf("invalid reference '%s'", reference)
+	}
+	return nil
+}
+
+// planOrderLines splits the amount and its fee across the addresses. Each
+// address pays as much as it can; the fee is paid first.
+func planOrderLines(addresses []string, available map[string]int64, amount int64) ([]orderLine, error) {
+	var lines []orderLine
+	remaining := amount
+	fee := utils.GetFee(amount)
+
+	for _, address := range addresses {
+		if remaining == 0 && fee == 0 {
+			break
+		}
+		funds := available[address]
+		if funds <= 0 {
+			continue
+		}
+
+		line := orderLine{Address: address}
+		line.Fee = min(fee, funds)
+		line.Amount = min(remaining, funds-line.Fee)
+		fee -= line.Fee
+		remaining -= line.Amount
+		lines = append(lines, line)
+	}
+
+	if remaining > 0 || fee > 0 {
+		return nil, fmt.Errorf("insufficient funds: missing %s", utils.ToNoso(remaining+fee))
+	}
+
+	err := verifyOrderLineFees(lines)
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// newLegacyOrder creates the unsigned transactions for the planned lines
+func newLegacyOrder(lines []orderLine, receiver, reference string, timeStamp, lastBlock int64) *LegacyOrder {
+	order := &LegacyOrder{
+		TimeStamp:    timeStamp,
+		Transactions: make([]LegacyTransaction, len(lines)),
+	}
+
+	// Like the wallet, every transfer ID hashes the amount still to be sent
+	// when its line is created, not the amount of the line
+	var remaining int64
+	for _, l := range lines {
+		remaining += l.Amount
+	}
+
+	transferIDs := make([]string, len(lines))
+	for i, l := range lines {
+		t := NewLegacyTransaction()
+		t.OrderLinesCount = int32(len(lines))
+		t.OrderType.SetString(cOrderTypeTransfer)
+		t.TimeStamp = timeStamp
+		t.Reference.SetString(reference)
+		t.TransferIndex = int32(i + 1)
+		t.Address.SetString(l.Address)
+		t.Receiver.SetString(receiver)
+		t.AmountFee = l.Fee
+		t.AmountTransfer = l.Amount
+		transferIDs[i] = transferID(timeStamp, l.Address, receiver, remaining, lastBlock)
+		t.TransferID.SetString(transferIDs[i])
+		remaining -= l.Amount
+		order.Transactions[i] = *t
+	}
+
+	order.OrderID = orderID(timeStamp, transferIDs)
+	for i := range order.Transactions {
+		order.Transactions[i].OrderID.SetString(order.OrderID)
+	}
+
+	return order
+}
+
+// transferID returns the ID of an order line sending the remaining amount of
+// the order from address
+func transferID(timeStamp int64, address, receiver string, remaining, lastBlock int64) string {
+	return utils.TransferHash(strconv.FormatInt(timeStamp, 10) + address + receiver +
+		strconv.FormatInt(remaining, 10) + strconv.FormatInt(lastBlock, 10))
+}
+
+// orderID returns the ID of an order made of the lines with the transfer IDs
+func orderID(timeStamp int64, transferIDs []string) string {
+	return utils.OrderHash(strconv.Itoa(len(transferIDs)) +
+		strconv.FormatInt(timeStamp, 10) + strings.Join(transferIDs, ""))
+}
+
+// signOrderLine sets the sender public key and signature of a line. Signing
+// stays disabled until the scheme is verified against orders from a node.
+func signOrderLine(t *LegacyTransaction, publicKey, privateKey string) error {
+	if !cOrderSigningVerified {
+		return ErrOrderSigningUnverified
+	}
+	return signLine(t, publicKey, privateKey)
+}
+
+// signLine sets the sender public key and signature of a line
+func signLine(t *LegacyTransaction, publicKey, privateKey string) error {
+	address := t.Address.GetString()
+	if utils.AddressFromPublicKey(publicKey) != address {
+		return fmt.Errorf("public key does not match address %s", address)
+	}
+
+	signature, err := utils.SignMessage(t.signatureMessage(), privateKey)
+	if err != nil {
+		return fmt.Errorf("cannot sign line %d: %s", t.TransferIndex, err)
+	}
+
+	err = t.Sender.SetString(publicKey)
+	if err != nil {
+		return err
+	}
+	return t.Signature.SetString(signature)
+}
@@ -0,0 +1,159 @@
+package legacy
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// nodeBlockFile is a block captured from a node, with at least one transfer
+// order: a copy of NOSODATA/BLOCKS/<number>.blk from a synced node. Signing
+// stays disabled until this test passes against it.
+var nodeBlockFile = filepath.Join("testdata", "node_block.blk")
+
+func TestNodeBlockOrders(t *testing.T) {
+	if !utils.FileExists(nodeBlockFile) {
+		if cOrderSigningVerified {
+			t.Fatalf("order signing is enabled without %s to verify it", nodeBlockFile)
+		}
+		t.Skipf("copy a block with a transfer order from a node to %s to verify order signing", nodeBlockFile)
+	}
+
+	var b LegacyBlock
+	err := b.ReadFromFile(nodeBlockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checked := 0
+	for _, o := range groupOrders(b.Transactions) {
+		if o.Transactions[0].OrderType.GetString() != cOrderTypeTransfer {
+			continue
+		}
+		for i := range o.Transactions {
+			err = verifyOrderLine(&o.Transactions[i])
+			if err != nil {
+				t.Errorf("order %s: %s", o.OrderID, err)
+			}
+		}
+
+		// The last block known to the sender is not stored, try the ones
+		// before the block
+		found := false
+		for lastBlock := b.Number - 10; lastBlock <= b.Number && !found; lastBlock++ {
+			found = rebuildOrderIDs(&o, lastBlock)
+		}
+		if !found {
+			t.Errorf("order %s: cannot rebuild the transfer and order IDs", o.OrderID)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("block has no transfer order")
+	}
+}
+
+// rebuildOrderIDs reports whether the transfer and order IDs of the order
+// match the ones computed for the last block
+func rebuildOrderIDs(o *LegacyOrder, lastBlock int64) bool {
+	var remaining int64
+	for i := range o.Transactions {
+		remaining += o.Transactions[i].AmountTransfer
+	}
+
+	transferIDs := make([]string, len(o.Transactions))
+	for i := range o.Transactions {
+		t := &o.Transactions[i]
+		transferIDs[i] = transferID(t.TimeStamp, t.Address.GetString(), t.Receiver.GetString(), remaining, lastBlock)
+		if transferIDs[i] != t.TransferID.GetString() {
+			return false
+		}
+		remaining -= t.AmountTransfer
+	}
+	return orderID(o.TimeStamp, transferIDs) == o.OrderID
+}
+
+func TestNewLegacyOrderTransferIDs(t *testing.T) {
+	const receiver = "N4DwRSTCFdNNV7JXuNfNYrbjipVauF6"
+	lines := []orderLine{
+		{Address: "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", Amount: 300, Fee: 10},
+		{Address: "N3wrVVsHCdific6bRuKqS7QW8ZiNhFP", Amount: 700, Fee: 0},
+	}
+	o := newLegacyOrder(lines, receiver, "ref", 1700000000, 120000)
+
+	// Every line hashes the amount still to be sent, not its own amount
+	want := []string{
+		transferID(1700000000, lines[0].Address, receiver, 1000, 120000),
+		transferID(1700000000, lines[1].Address, receiver, 700, 120000),
+	}
+	for i := range o.Transactions {
+		if got := o.Transactions[i].TransferID.GetString(); got != want[i] {
+			t.Errorf("line %d: transfer ID %s, want %s", i+1, got, want[i])
+		}
+	}
+	if o.OrderID != orderID(1700000000, want) {
+		t.Errorf("order ID %s, want %s", o.OrderID, orderID(1700000000, want))
+	}
+	if !rebuildOrderIDs(o, 120000) {
+		t.Error("cannot rebuild the IDs of the order")
+	}
+}
+
+func TestSignLine(t *testing.T) {
+	privateKey, publicKey, err := utils.NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := utils.AddressFromPublicKey(publicKey)
+	o := newLegacyOrder([]orderLine{{Address: address, Amount: 1000, Fee: 10}},
+		"N4DwRSTCFdNNV7JXuNfNYrbjipVauF6", "", 1700000000, 120000)
+
+	line := &o.Transactions[0]
+	err = signLine(line, publicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = verifyOrderLine(line)
+	if err != nil {
+		t.Error(err)
+	}
+
+	line.AmountTransfer++
+	if verifyOrderLine(line) == nil {
+		t.Error("signature verifies for a changed line")
+	}
+}
+
+func TestOrderSigningUnverified(t *testing.T) {
+	if cOrderSigningVerified {
+		t.Skip("order signing is verified")
+	}
+
+	a, err := NewLegacyWalletAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Balance = 100000
+
+	_, err = NewLegacyOrderBuilder("N4DwRSTCFdNNV7JXuNfNYrbjipVauF6", 1000, "", *a).Build()
+	if !errors.Is(err, ErrOrderSigningUnverified) {
+		t.Errorf("got %v, want %v", err, ErrOrderSigningUnverified)
+	}
+}
+
+func TestValidateOrderReceiver(t *testing.T) {
+	address := cTestKeys[1].Address
+	typo := address[:len(address)-3] + "zz" + address[len(address)-1:]
+
+	if err := validateOrder(address, 1000, ""); err != nil {
+		t.Errorf("address: %s", err)
+	}
+	if err := validateOrder("payouts", 1000, ""); err != nil {
+		t.Errorf("alias: %s", err)
+	}
+	if validateOrder(typo, 1000, "") == nil {
+		t.Errorf("mistyped address %s accepted", typo)
+	}
+}
@@ -0,0 +1,48 @@
+package legacy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	cMaxAnsiStringLength = 1 << 20
+)
+
+// readAnsiString reads a variable length Pascal string, stored as a 32 bit
+// length followed by the characters
+func readAnsiString(r io.Reader) (string, error) {
+	var length int32
+	err := binary.Read(r, binary.LittleEndian, &length)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || length > cMaxAnsiStringLength {
+		return "", fmt.Errorf("invalid string length %d", length)
+	}
+
+	data := make([]byte, length)
+	_, err = io.ReadFull(r, data)
+	if err == io.EOF {
+		return "", io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// writeAnsiString writes a variable length Pascal string
+func writeAnsiString(w io.Writer, s string) error {
+	if len(s) > cMaxAnsiStringLength {
+		return errors.New("string exceeds maximum length")
+	}
+	err := binary.Write(w, binary.LittleEndian, int32(len(s)))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, s)
+	return err
+}
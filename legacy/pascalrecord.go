@@ -0,0 +1,231 @@
+package legacy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PascalFieldType is the Pascal type of a record field
+type PascalFieldType int
+
+const (
+	PascalByteField        PascalFieldType = iota // byte
+	PascalInt32Field                              // integer, longint
+	PascalInt64Field                              // int64
+	PascalShortStringField                        // string[Capacity]
+)
+
+// PascalField describes one field of a Pascal record declaration
+type PascalField struct {
+	Name     string
+	Type     PascalFieldType
+	Capacity int // Only for short strings
+}
+
+// size returns the number of bytes the field takes
+func (f PascalField) size() int {
+	switch f.Type {
+	case PascalInt32Field:
+		return 4
+	case PascalInt64Field:
+		return 8
+	case PascalShortStringField:
+		return f.Capacity + 1
+	}
+	return 1
+}
+
+// alignment returns the natural alignment of the field; short strings are
+// byte arrays and need none
+func (f PascalField) alignment() int {
+	switch f.Type {
+	case PascalInt32Field:
+		return 4
+	case PascalInt64Field:
+		return 8
+	}
+	return 1
+}
+
+// PascalRecord is the layout of a Pascal record. Packed records have no
+// padding; other records align every field to its natural alignment and pad
+// the end to the largest one, as Free Pascal does.
+//
+// Only the MN lock record is not packed. The summary and wallet accounts, the
+// GVT entries and the block transactions are packed records, read field by
+// field without padding; the headers use a packed PascalRecord.
+type PascalRecord struct {
+	Fields  []PascalField
+	Packed  bool
+	offsets map[string]int
+	size    int
+}
+
+// NewPascalRecord computes the layout of a record declaration
+func NewPascalRecord(packed bool, fields ...PascalField) *PascalRecord {
+	r := &PascalRecord{
+		Fields:  fields,
+		Packed:  packed,
+		offsets: make(map[string]int, len(fields)),
+	}
+
+	offset := 0
+	maxAlign := 1
+	for _, f := range fields {
+		if !packed {
+			offset = alignTo(offset, f.alignment())
+			maxAlign = max(maxAlign, f.alignment())
+		}
+		r.offsets[f.Name] = offset
+		offset += f.size()
+	}
+	if !packed {
+		offset = alignTo(offset, maxAlign)
+	}
+	r.size = offset
+
+	return r
+}
+
+// Size returns the number of bytes of the record, padding included
+func (r *PascalRecord) Size() int {
+	return r.size
+}
+
+// Offset returns the position of a field in the record, or -1 when unknown
+func (r *PascalRecord) Offset(name string) int {
+	offset, ok := r.offsets[name]
+	if !ok {
+		return -1
+	}
+	return offset
+}
+
+// Padding returns the number of padding bytes in the record
+func (r *PascalRecord) Padding() int {
+	padding := r.size
+	for _, f := range r.Fields {
+		padding -= f.size()
+	}
+	return padding
+}
+
+// NewData creates a zeroed record with this layout
+func (r *PascalRecord) NewData() *PascalRecordData {
+	return &PascalRecordData{
+		layout: r,
+		raw:    make([]byte, r.size),
+	}
+}
+
+// field returns the declaration and offset of a field and checks its type
+func (r *PascalRecord) field(name string, t PascalFieldType) (PascalField, int, error) {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			if f.Type != t {
+				return f, 0, fmt.Errorf("field %s has another type", name)
+			}
+			return f, r.offsets[name], nil
+		}
+	}
+	return PascalField{}, 0, fmt.Errorf("unknown field %s", name)
+}
+
+// PascalRecordData holds the raw bytes of one record, so padding bytes read
+// from a file are written back unchanged
+type PascalRecordData struct {
+	layout *PascalRecord
+	raw    []byte
+}
+
+// ReadFromStream reads one whole record from the stream
+func (d *PascalRecordData) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	_, err := io.ReadFull(r, d.raw)
+	return err
+}
+
+// WriteToStream writes the record, padding included
+func (d *PascalRecordData) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	_, err := w.Write(d.raw)
+	return err
+}
+
+// Int32 returns the value of an integer field
+func (d *PascalRecordData) Int32(name string) (int32, error) {
+	_, offset, err := d.layout.field(name, PascalInt32Field)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(d.raw[offset:])), nil
+}
+
+// SetInt32 sets the value of an integer field
+func (d *PascalRecordData) SetInt32(name string, v int32) error {
+	_, offset, err := d.layout.field(name, PascalInt32Field)
+	if err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint32(d.raw[offset:], uint32(v))
+	return nil
+}
+
+// Int64 returns the value of an int64 field
+func (d *PascalRecordData) Int64(name string) (int64, error) {
+	_, offset, err := d.layout.field(name, PascalInt64Field)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint64(d.raw[offset:])), nil
+}
+
+// SetInt64 sets the value of an int64 field
+func (d *PascalRecordData) SetInt64(name string, v int64) error {
+	_, offset, err := d.layout.field(name, PascalInt64Field)
+	if err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(d.raw[offset:], uint64(v))
+	return nil
+}
+
+// ShortString returns the value of a short string field
+func (d *PascalRecordData) ShortString(name string) (PascalShortString, error) {
+	f, offset, err := d.layout.field(name, PascalShortStringField)
+	if err != nil {
+		return PascalShortString{}, err
+	}
+	p := NewPascalShortString(f.Capacity)
+	err = p.ReadFromStream(bytes.NewReader(d.raw[offset : offset+f.size()]))
+	return *p, err
+}
+
+// SetShortString sets the value of a short string field, garbage included
+func (d *PascalRecordData) SetShortString(name string, p *PascalShortString) error {
+	f, offset, err := d.layout.field(name, PascalShortStringField)
+	if err != nil {
+		return err
+	}
+	if p.capacity != f.Capacity {
+		return fmt.Errorf("field %s has capacity %d, got %d", name, f.Capacity, p.capacity)
+	}
+	copy(d.raw[offset:offset+f.size()], p.data)
+	return nil
+}
+
+// alignTo rounds offset up to a multiple of align
+func alignTo(offset, align int) int {
+	return (offset + align - 1) / align * align
+}
@@ -0,0 +1,38 @@
+package legacy
+
+import (
+	"testing"
+)
+
+func TestPascalRecordLayout(t *testing.T) {
+	if size := cHeaderRecord.Size(); size != 70 {
+		t.Errorf("header record: got %d bytes, want 70", size)
+	}
+	if size, padding := cMNLockRecord.Size(), cMNLockRecord.Padding(); size != 40 || padding != 3 {
+		t.Errorf("MN lock record: got %d bytes with %d padding, want 40 with 3", size, padding)
+	}
+	if offset := cMNLockRecord.Offset("Expire"); offset != 36 {
+		t.Errorf("MN lock expire: got offset %d, want 36", offset)
+	}
+}
+
+func TestPascalRecordDataErrors(t *testing.T) {
+	d := cHeaderRecord.NewData()
+
+	_, err := d.Int32("Missing")
+	if err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	_, err = d.Int64("Block")
+	if err == nil {
+		t.Error("expected an error for a field of another type")
+	}
+	err = d.SetInt32("BlockHash", 1)
+	if err == nil {
+		t.Error("expected an error for a field of another type")
+	}
+	err = d.SetShortString("BlockHash", NewPascalShortString(40))
+	if err == nil {
+		t.Error("expected an error for a string of another capacity")
+	}
+}
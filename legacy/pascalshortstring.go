@@ -11,6 +11,7 @@ type PascalShortString struct {
 	data     []byte // Contains raw bytes, including the length and garbage
 	length   uint8  // Cached length for quick access (matches the first byte of Data)
 	capacity int    // Maximum capacity (e.g., 20 for string[20])
+	clamped  bool   // Set when the length byte read exceeded the capacity
 }
 
 // NewPascalShortString creates a new PascalShortString with a given capacity
@@ -38,9 +39,11 @@ func (p *PascalShortString) ReadFromStream(r io.Reader) error {
 	if err != nil {
 		return err
 	}
+	p.clamped = false
 	if int(p.data[0]) > p.capacity {
 		// return fmt.Errorf("capacity is %d, but read length is %d", p.Capacity, p.Data[0])
 		p.data[0] = byte(p.capacity)
+		p.clamped = true
 	}
 
 	// Update the Length field from the first byte of Data
@@ -90,6 +93,18 @@ func (p *PascalShortString) SetString(s string) error {
 	return nil
 }
 
+// Clone returns a copy that does not share the raw bytes
+func (p *PascalShortString) Clone() PascalShortString {
+	c := *p
+	c.data = append([]byte(nil), p.data...)
+	return c
+}
+
+// Clamped reports whether the length byte was clamped to the capacity on the last read
+func (p *PascalShortString) Clamped() bool {
+	return p.clamped
+}
+
 // GetString returns the actual string part (up to the length byte)
 func (p *PascalShortString) GetString() string {
 	return string(p.data[1 : 1+int(p.length)])
@@ -31,29 +31,40 @@ func (p *LegacyPSO) ReadFromFile(f string) error {
 	}
 	defer file.Close()
 
+	return p.ReadFromStream(file)
+}
+
+// ReadFromStream reads the header, the MN locks and the PSO items from a stream
+func (p *LegacyPSO) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
 	// Field Block
-	err = binary.Read(file, binary.LittleEndian, &p.Block)
+	err := binary.Read(r, binary.LittleEndian, &p.Block)
 	if err != nil {
 		return err
 	}
 
 	// Field MNLockCount
-	err = binary.Read(file, binary.LittleEndian, &p.MNLockCount)
+	err = binary.Read(r, binary.LittleEndian, &p.MNLockCount)
 	if err != nil {
 		return err
 	}
 
 	// Field PSOCount
-	err = binary.Read(file, binary.LittleEndian, &p.PSOCount)
+	err = binary.Read(r, binary.LittleEndian, &p.PSOCount)
 	if err != nil {
 		return err
 	}
 
 	// Field MNLocks
+	p.MNLocks = nil
 	if p.MNLockCount > 0 {
 		for i := 0; i < int(p.MNLockCount); i++ {
 			mli := LegacyMNLockItem{}
-			err := mli.ReadFromStream(file)
+			err := mli.ReadFromStream(r)
 			if err == io.EOF {
 				break
 			}
@@ -65,19 +76,63 @@ func (p *LegacyPSO) ReadFromFile(f string) error {
 	}
 
 	// Field PSOS
-	// if p.PSOCount > 0 {
-	// 	for i := 0; i < int(p.PSOCount); i++ {
-	// 		psoi := LegacyPSOItem{}
-	// 		err := psoi.ReadFromStream(file)
-	// 		if err == io.EOF {
-	// 			break
-	// 		}
-	// 		if err != nil {
-	// 			return err
-	// 		}
-	// 		p.PSOS = append(p.PSOS, psoi)
-	// 	}
-	// }
+	p.PSOS = nil
+	if p.PSOCount > 0 {
+		for i := 0; i < int(p.PSOCount); i++ {
+			psoi := LegacyPSOItem{}
+			err := psoi.ReadFromStream(r)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			p.PSOS = append(p.PSOS, psoi)
+		}
+	}
+
+	return nil
+}
+
+// WriteToFile writes the PSO data to a file
+func (p *LegacyPSO) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0644, p.WriteToStream)
+}
+
+// WriteToStream writes the header, the MN locks and the PSO items to a stream.
+// The counts are taken from the slices.
+func (p *LegacyPSO) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	p.MNLockCount = int32(len(p.MNLocks))
+	p.PSOCount = int32(len(p.PSOS))
+
+	// Fields Block, MNLockCount and PSOCount
+	for _, v := range []int32{p.Block, p.MNLockCount, p.PSOCount} {
+		err := binary.Write(w, binary.LittleEndian, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Field MNLocks
+	for i := range p.MNLocks {
+		err := p.MNLocks[i].WriteToStream(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Field PSOS
+	for i := range p.PSOS {
+		err := p.PSOS[i].WriteToStream(w)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -91,26 +146,44 @@ func (p *LegacyPSO) AsJSON() string {
 	return string(jsonData)
 }
 
+// cMNLockRecord is the Pascal declaration of a MN lock, a non packed record:
+//
+//	TMNsLock = record
+//	  address : string[32];
+//	  expire  : integer;
+//	end;
+var cMNLockRecord = NewPascalRecord(false,
+	PascalField{Name: "Address", Type: PascalShortStringField, Capacity: 32},
+	PascalField{Name: "Expire", Type: PascalInt32Field},
+)
+
 type LegacyMNLockItem struct {
-	Address PascalShortString `json:"address"` // Capacity 32 aligned makes it 35
-	Expire  int32             `json:"expire"`
+	Address PascalShortString `json:"address"` // Capacity 32
+	Expire  int32             `json:"expire"`  // Blocks after the PSO snapshot block
+
+	record *PascalRecordData // Raw record, keeps the padding bytes
 }
 
-func (m *LegacyMNLockItem) ReadFromStream(f *os.File) error {
+func (m *LegacyMNLockItem) ReadFromStream(f io.Reader) error {
 	// Check if the stream is nil
 	if f == nil {
 		return errors.New("nil reader provided")
 	}
 
+	m.record = cMNLockRecord.NewData()
+	err := m.record.ReadFromStream(f)
+	if err != nil {
+		return err
+	}
+
 	// Field Address
-	m.Address = *NewPascalShortString(35)
-	err := m.Address.ReadFromStream(f)
+	m.Address, err = m.record.ShortString("Address")
 	if err != nil {
 		return err
 	}
 
 	// Field Expire
-	err = binary.Read(f, binary.LittleEndian, &m.Expire)
+	m.Expire, err = m.record.Int32("Expire")
 	if err != nil {
 		return err
 	}
@@ -118,6 +191,33 @@ func (m *LegacyMNLockItem) ReadFromStream(f *os.File) error {
 	return nil
 }
 
+// WriteToStream writes the MN lock record to a stream, keeping the padding
+// bytes of the record it was read from
+func (m *LegacyMNLockItem) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	if m.record == nil {
+		m.record = cMNLockRecord.NewData()
+	}
+
+	// Field Address
+	err := m.record.SetShortString("Address", &m.Address)
+	if err != nil {
+		return err
+	}
+
+	// Field Expire
+	err = m.record.SetInt32("Expire", m.Expire)
+	if err != nil {
+		return err
+	}
+
+	return m.record.WriteToStream(w)
+}
+
 type LegacyPSOItem struct {
 	Mode    int32  `json:"mode"`
 	Hash    string `json:"hash"`
@@ -126,3 +226,87 @@ type LegacyPSOItem struct {
 	Members string `json:"members"`
 	Params  string `json:"params"`
 }
+
+// ReadFromStream reads a PSO item; the strings are variable length
+func (i *LegacyPSOItem) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	// Field Mode
+	err := binary.Read(r, binary.LittleEndian, &i.Mode)
+	if err != nil {
+		return err
+	}
+
+	// Field Hash
+	i.Hash, err = readAnsiString(r)
+	if err != nil {
+		return err
+	}
+
+	// Field Owner
+	i.Owner, err = readAnsiString(r)
+	if err != nil {
+		return err
+	}
+
+	// Field Expire
+	err = binary.Read(r, binary.LittleEndian, &i.Expire)
+	if err != nil {
+		return err
+	}
+
+	// Field Members
+	i.Members, err = readAnsiString(r)
+	if err != nil {
+		return err
+	}
+
+	// Field Params
+	i.Params, err = readAnsiString(r)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteToStream writes a PSO item to a stream
+func (i *LegacyPSOItem) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	// Field Mode
+	err := binary.Write(w, binary.LittleEndian, i.Mode)
+	if err != nil {
+		return err
+	}
+
+	// Fields Hash and Owner
+	for _, s := range []string{i.Hash, i.Owner} {
+		err = writeAnsiString(w, s)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Field Expire
+	err = binary.Write(w, binary.LittleEndian, i.Expire)
+	if err != nil {
+		return err
+	}
+
+	// Fields Members and Params
+	for _, s := range []string{i.Members, i.Params} {
+		err = writeAnsiString(w, s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
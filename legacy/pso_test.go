@@ -0,0 +1,56 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPSORoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "psos.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p LegacyPSO
+	err = p.ReadFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Block != 120000 || len(p.MNLocks) != 2 || len(p.PSOS) != 2 {
+		t.Fatalf("got block %d, %d locks, %d PSOs", p.Block, len(p.MNLocks), len(p.PSOS))
+	}
+	if l := p.MNLocks[0]; l.Address.GetString() != "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9" || l.Expire != 500 {
+		t.Errorf("lock: got %s %d", l.Address.GetString(), l.Expire)
+	}
+	if i := p.PSOS[0]; i.Mode != 1 || i.Owner != "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9" || i.Params != "fee:10" {
+		t.Errorf("PSO: got %+v", i)
+	}
+
+	var out bytes.Buffer
+	err = p.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Error("written PSO file differs from the original file")
+	}
+
+	f := filepath.Join(t.TempDir(), "psos.dat")
+	err = p.WriteToFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) || info.Mode().Perm() != 0644 {
+		t.Errorf("written PSO file differs from the original file, permissions %o", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,99 @@
+package legacy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cPSOMembersSeparator = ","
+)
+
+// LegacyTypedPSO is a PSO item with its members parsed. Typed modes and the
+// parsing of Params are not implemented: the modes and the params format are
+// defined by the node PSO code, which this package has no copy of, so both
+// are kept raw and left to the caller.
+type LegacyTypedPSO struct {
+	Mode    int32    `json:"mode"`
+	Hash    string   `json:"hash"`
+	Owner   string   `json:"owner"`
+	Expire  int64    `json:"expire"` // Block the item expires at
+	Members []string `json:"members"`
+	Params  string   `json:"params"`
+}
+
+// ActiveAt reports whether the item is in force at the block
+func (t *LegacyTypedPSO) ActiveAt(block int64) bool {
+	return block < t.Expire
+}
+
+// MembersList returns the comma separated members as a list
+func (i *LegacyPSOItem) MembersList() []string {
+	var members []string
+	for _, m := range strings.Split(i.Members, cPSOMembersSeparator) {
+		m = strings.TrimSpace(m)
+		if m != "" {
+			members = append(members, m)
+		}
+	}
+	return members
+}
+
+// Typed returns the item with its members parsed
+func (i *LegacyPSOItem) Typed() *LegacyTypedPSO {
+	return &LegacyTypedPSO{
+		Mode:    i.Mode,
+		Hash:    i.Hash,
+		Owner:   i.Owner,
+		Expire:  int64(i.Expire),
+		Members: i.MembersList(),
+		Params:  i.Params,
+	}
+}
+
+// Validate checks the owner, members and expiry of the item against the PSO
+// snapshot block and returns the problems found
+func (i *LegacyPSOItem) Validate(block int32) []string {
+	var issues []string
+	add := func(format string, args ...any) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	if !utils.IsValidAddress(i.Owner) {
+		add("invalid owner address '%s'", i.Owner)
+	}
+	if i.Expire <= block {
+		add("expired at block %d, before block %d", i.Expire, block)
+	}
+	for _, m := range i.MembersList() {
+		if !utils.IsValidAddress(m) {
+			add("invalid member address '%s'", m)
+		}
+	}
+
+	return issues
+}
+
+// Validate checks every PSO item and returns the problems found by position
+func (p *LegacyPSO) Validate() map[int][]string {
+	issues := make(map[int][]string)
+	for n := range p.PSOS {
+		if i := p.PSOS[n].Validate(p.Block); len(i) > 0 {
+			issues[n] = i
+		}
+	}
+	return issues
+}
+
+// ActiveAt returns the PSO items in force at the block
+func (p *LegacyPSO) ActiveAt(block int64) []LegacyTypedPSO {
+	var active []LegacyTypedPSO
+	for n := range p.PSOS {
+		if t := p.PSOS[n].Typed(); t.ActiveAt(block) {
+			active = append(active, *t)
+		}
+	}
+	return active
+}
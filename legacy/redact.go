@@ -0,0 +1,197 @@
+package legacy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// RedactedValue replaces sensitive values in every rendering
+	RedactedValue = "<redacted>"
+
+	// cSensitiveTag is the struct tag marking a field as sensitive, e.g. `sensitive:"true"`
+	cSensitiveTag = "sensitive"
+)
+
+// RenderOptions controls how records are rendered as text or JSON
+type RenderOptions struct {
+	ShowSecrets bool // Render fields tagged as sensitive instead of redacting them
+}
+
+// Redact returns value, or RedactedValue unless the options allow secrets
+func (o RenderOptions) Redact(value string) string {
+	if o.ShowSecrets {
+		return value
+	}
+	return RedactedValue
+}
+
+// renderJSON renders v as indented JSON, redacting the fields tagged as
+// sensitive unless the options allow secrets
+func renderJSON(v any, opts RenderOptions) string {
+	var jsonData []byte
+	var err error
+	if opts.ShowSecrets {
+		jsonData, err = json.Marshal(v)
+	} else {
+		jsonData, err = marshalRedacted(reflect.ValueOf(v))
+	}
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+
+	var out bytes.Buffer
+	err = json.Indent(&out, jsonData, "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return out.String()
+}
+
+// marshalRedacted encodes v like json.Marshal, replacing the values of the
+// fields tagged as sensitive
+func marshalRedacted(v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return []byte("null"), nil
+	}
+	if !hasSensitive(v.Type(), nil) {
+		// Addressable values keep their pointer receiver marshalers
+		if v.CanAddr() {
+			return json.Marshal(v.Addr().Interface())
+		}
+		return json.Marshal(v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		return marshalRedacted(v.Elem())
+
+	case reflect.Struct:
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		t := v.Type()
+		first := true
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := strings.Split(f.Tag.Get("json"), ",")
+			name := tag[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if len(tag) > 1 && tag[1] == "omitempty" && v.Field(i).IsZero() {
+				continue
+			}
+
+			var value []byte
+			var err error
+			if f.Tag.Get(cSensitiveTag) == "true" {
+				value = []byte(`"` + RedactedValue + `"`)
+			} else {
+				value, err = marshalRedacted(v.Field(i))
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			key, _ := json.Marshal(name)
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []byte("null"), nil
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			value, err := marshalRedacted(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(value)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			value, err := marshalRedacted(v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			key, _ := json.Marshal(fmt.Sprint(k))
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	}
+
+	return json.Marshal(v.Interface())
+}
+
+// hasSensitive reports whether the type contains a field tagged as sensitive
+func hasSensitive(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen == nil {
+		seen = make(map[reflect.Type]bool)
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return hasSensitive(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if f.Tag.Get(cSensitiveTag) == "true" || hasSensitive(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
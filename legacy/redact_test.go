@@ -0,0 +1,54 @@
+package legacy
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWalletAsJSONRedacted(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := w.AsJSON()
+	for _, k := range cTestKeys[:2] {
+		if strings.Contains(redacted, k.PrivateKey) {
+			t.Errorf("%s: private key rendered by default", k.Address)
+		}
+		if !strings.Contains(redacted, k.PublicKey) {
+			t.Errorf("%s: public key not rendered", k.Address)
+		}
+	}
+	if strings.Count(redacted, RedactedValue) != 2 {
+		t.Errorf("redacted values: got %d, want 2", strings.Count(redacted, RedactedValue))
+	}
+	if !json.Valid([]byte(redacted)) {
+		t.Error("redacted wallet is not valid JSON")
+	}
+
+	shown := w.AsJSONWithOptions(RenderOptions{ShowSecrets: true})
+	for _, k := range cTestKeys[:2] {
+		if !strings.Contains(shown, k.PrivateKey) {
+			t.Errorf("%s: private key not rendered with secrets shown", k.Address)
+		}
+	}
+	if strings.Contains(shown, RedactedValue) {
+		t.Error("value redacted with secrets shown")
+	}
+}
+
+func TestConfigAsJSONRedacted(t *testing.T) {
+	c := NewLegacyConfig()
+	c.PoolPassword = "hunter2"
+
+	if s := c.AsJSON(); strings.Contains(s, "hunter2") || !strings.Contains(s, RedactedValue) {
+		t.Errorf("password rendered by default: %s", s)
+	}
+	if s := c.AsJSONWithOptions(RenderOptions{ShowSecrets: true}); !strings.Contains(s, "hunter2") {
+		t.Errorf("password not rendered with secrets shown: %s", s)
+	}
+}
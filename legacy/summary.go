@@ -1,17 +1,21 @@
 package legacy
 
 import (
+	"crypto/md5"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/Friends-Of-Noso/NosoData-Go/utils"
 )
 
 type LegacySummary struct {
+	Block         int64                  `json:"block"` // -1 until located in the headers
+	Hash          string                 `json:"hash"`  // MD5 of the file, as stored in the headers
 	AccountsCount int64                  `json:"accounts-count"`
 	Accounts      []LegacySummaryAccount `json:"accounts"`
 }
@@ -28,9 +32,26 @@ func (s *LegacySummary) ReadFromFile(f string) error {
 	}
 	defer file.Close()
 
+	return s.ReadFromStream(file)
+}
+
+// ReadFromStream reads the account records from a stream. The file does not
+// store its block number; use SetBlockFromHeaders to find it.
+func (s *LegacySummary) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
+	s.Block = -1
+	s.AccountsCount = 0
+	s.Accounts = nil
+
+	hash := md5.New()
+	r = io.TeeReader(r, hash)
 	for {
 		a := LegacySummaryAccount{}
-		err := a.ReadFromStream(file)
+		err := a.ReadFromStream(r)
 		if err == io.EOF {
 			break
 		}
@@ -40,7 +61,34 @@ func (s *LegacySummary) ReadFromFile(f string) error {
 		s.AccountsCount += 1
 		s.Accounts = append(s.Accounts, a)
 	}
+	s.Hash = strings.ToUpper(fmt.Sprintf("%x", hash.Sum(nil)))
+
+	return nil
+}
+
+// ErrSummaryBlockUnknown is returned when a summary is used before its block
+// is located with SetBlockFromHeaders
+var ErrSummaryBlockUnknown = errors.New("summary block unknown, locate it in the headers first")
+
+// SetBlockFromHeaders sets the block of the summary to the last header whose
+// summary hash matches the file hash
+func (s *LegacySummary) SetBlockFromHeaders(h *LegacyHeaders) error {
+	for i := len(h.Headers) - 1; i >= 0; i-- {
+		if strings.EqualFold(h.Headers[i].SummaryHash.GetString(), s.Hash) {
+			s.Block = int64(h.Headers[i].Block)
+			return nil
+		}
+	}
+	return fmt.Errorf("summary hash %s not found in the headers", s.Hash)
+}
 
+// AccountByAddress returns the account with the given address, or nil
+func (s *LegacySummary) AccountByAddress(address string) *LegacySummaryAccount {
+	for i := range s.Accounts {
+		if s.Accounts[i].Hash.GetString() == address {
+			return &s.Accounts[i]
+		}
+	}
 	return nil
 }
 
@@ -61,7 +109,7 @@ type LegacySummaryAccount struct {
 	LastOperation int64             `json:"last-operation"`
 }
 
-func (a *LegacySummaryAccount) ReadFromStream(f *os.File) error {
+func (a *LegacySummaryAccount) ReadFromStream(f io.Reader) error {
 	// Check if the stream is nil
 	if f == nil {
 		return errors.New("nil reader provided")
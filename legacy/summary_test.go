@@ -0,0 +1,90 @@
+package legacy
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSummaryReadFromFile(t *testing.T) {
+	var s LegacySummary
+	err := s.ReadFromFile(filepath.Join("testdata", "sumary.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.AccountsCount != 3 || len(s.Accounts) != 3 {
+		t.Fatalf("accounts: got %d, want 3", s.AccountsCount)
+	}
+	if s.Block != -1 {
+		t.Errorf("block: got %d, want -1 before locating the summary", s.Block)
+	}
+	if s.Hash != "190F75EE2C3AD7EA07FE6281DF10530D" {
+		t.Errorf("hash: got %s", s.Hash)
+	}
+
+	a := s.AccountByAddress("N4DwRSTCFdNNV7JXuNfNYrbjipVauF6")
+	if a == nil {
+		t.Fatal("account not found")
+	}
+	if a.Custom.GetString() != "payouts" || a.Balance != 123456789 || a.LastOperation != 120000 {
+		t.Errorf("account: got %+v", a)
+	}
+}
+
+func TestSummarySetBlockFromHeaders(t *testing.T) {
+	var s LegacySummary
+	err := s.ReadFromFile(filepath.Join("testdata", "sumary.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := func(block int32, summaryHash string) LegacyHeader {
+		h := LegacyHeader{
+			Block:       block,
+			BlockHash:   *NewPascalShortString(32),
+			SummaryHash: *NewPascalShortString(32),
+		}
+		h.SummaryHash.SetString(summaryHash)
+		return h
+	}
+	h := LegacyHeaders{Headers: []LegacyHeader{
+		header(119999, "00000000000000000000000000000000"),
+		header(120000, "190f75ee2c3ad7ea07fe6281df10530d"),
+		header(120001, "11111111111111111111111111111111"),
+	}}
+
+	err = s.SetBlockFromHeaders(&h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Block != 120000 {
+		t.Errorf("block: got %d, want 120000", s.Block)
+	}
+
+	h.Headers = h.Headers[:1]
+	if s.SetBlockFromHeaders(&h) == nil {
+		t.Error("expected an error when the hash is not in the headers")
+	}
+}
+
+func TestSummaryBlockUnknown(t *testing.T) {
+	var s LegacySummary
+	err := s.ReadFromFile(filepath.Join("testdata", "sumary.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewLegacyAliasRegistry().SeedFromSummary(&s)
+	if !errors.Is(err, ErrSummaryBlockUnknown) {
+		t.Errorf("alias registry: got %v", err)
+	}
+	err = NewLegacyBalanceHistory(10).SeedFromSummary(&s)
+	if !errors.Is(err, ErrSummaryBlockUnknown) {
+		t.Errorf("balance history: got %v", err)
+	}
+	_, err = NewLegacyUnsignedOrder(&s, []string{cTestKeys[0].Address}, cTestKeys[1].Address, 1000, "")
+	if !errors.Is(err, ErrSummaryBlockUnknown) {
+		t.Errorf("unsigned order: got %v", err)
+	}
+}
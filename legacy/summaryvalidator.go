@@ -0,0 +1,99 @@
+package legacy
+
+import (
+	"fmt"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// LegacySummaryIssueKind identifies the kind of problem found in a summary
+type LegacySummaryIssueKind string
+
+const (
+	SummaryIssueDuplicateAddress LegacySummaryIssueKind = "duplicate-address"
+	SummaryIssueDuplicateCustom  LegacySummaryIssueKind = "duplicate-custom"
+	SummaryIssueNegativeBalance  LegacySummaryIssueKind = "negative-balance"
+	SummaryIssueInvalidAddress   LegacySummaryIssueKind = "invalid-address"
+	SummaryIssueFutureOperation  LegacySummaryIssueKind = "future-operation"
+	SummaryIssueClampedString    LegacySummaryIssueKind = "clamped-string"
+)
+
+// LegacySummaryIssue describes a problem found in one summary account
+type LegacySummaryIssue struct {
+	Position int                    `json:"position"`
+	Address  string                 `json:"address"`
+	Kind     LegacySummaryIssueKind `json:"kind"`
+	Message  string                 `json:"message"`
+}
+
+// String returns a human readable description of the issue
+func (i LegacySummaryIssue) String() string {
+	return fmt.Sprintf("position %d (%s): %s: %s", i.Position, i.Address, i.Kind, i.Message)
+}
+
+// Validate runs the integrity checks over the summary accounts.
+// Operations after tip are flagged; when tip is negative the summary block
+// number is used, and when that is unknown the check is skipped.
+func (s *LegacySummary) Validate(tip int64) []LegacySummaryIssue {
+	if tip < 0 {
+		tip = s.Block
+	}
+
+	var issues []LegacySummaryIssue
+	addresses := make(map[string]int)
+	customs := make(map[string]int)
+
+	for i := range s.Accounts {
+		a := &s.Accounts[i]
+		address := a.Hash.GetString()
+		custom := a.Custom.GetString()
+
+		add := func(kind LegacySummaryIssueKind, format string, args ...any) {
+			issues = append(issues, LegacySummaryIssue{
+				Position: i,
+				Address:  address,
+				Kind:     kind,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		// Field Hash
+		if first, ok := addresses[address]; ok {
+			add(SummaryIssueDuplicateAddress, "address already present at position %d", first)
+		} else {
+			addresses[address] = i
+		}
+		if !utils.IsValidAddress(address) {
+			add(SummaryIssueInvalidAddress, "address checksum does not match")
+		}
+
+		// Field Custom
+		if custom != "" {
+			if first, ok := customs[custom]; ok {
+				add(SummaryIssueDuplicateCustom, "alias '%s' already used at position %d", custom, first)
+			} else {
+				customs[custom] = i
+			}
+		}
+
+		// Field Balance
+		if a.Balance < 0 {
+			add(SummaryIssueNegativeBalance, "balance is %s", utils.ToNoso(a.Balance))
+		}
+
+		// Field LastOperation
+		if tip >= 0 && a.LastOperation > tip {
+			add(SummaryIssueFutureOperation, "last operation %d is beyond block %d", a.LastOperation, tip)
+		}
+
+		// Clamped length bytes
+		if a.Hash.Clamped() {
+			add(SummaryIssueClampedString, "hash length byte exceeded its capacity")
+		}
+		if a.Custom.Clamped() {
+			add(SummaryIssueClampedString, "custom length byte exceeded its capacity")
+		}
+	}
+
+	return issues
+}
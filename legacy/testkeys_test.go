@@ -0,0 +1,36 @@
+package legacy
+
+import (
+	"testing"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// cTestKeys are the key pairs the addresses of the test fixtures are derived
+// from
+var cTestKeys = []struct {
+	Address    string
+	PrivateKey string
+	PublicKey  string
+}{
+	{"N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9", "XAY6/MhQTf/1BWWlFKQzzd9BiJzU73V3t3eGfuuph7g=", "BC1Vn/LF2ORCalsTZCWIp6aX7FTGeY0W4UaN5kNNsj5haKY0WCPsu9+Rn8ug2VN2StsBGoMEGiZ47WtR43c82EI="},
+	{"N4DwRSTCFdNNV7JXuNfNYrbjipVauF6", "DoZXADvr21Du1OE2jn6BJDJcEya2fTrHi0c+L8CoZ5o=", "BIpmP10/9DVkyXTxXUe99MsyPmFKrMb0fsRUl49LkyR+97CmBS+Btn2FEXgTzlkHmFdeOZUSMEA6Uk6URJvESh8="},
+	{"N2x5DL4sR5yBJo9Uq5U2W5iqdR6ZdCa", "Ewqe8moemH1W5lsIsnxMC8zOrNcVUFSOb2ldVde58bI=", "BC5pYQmZ5DQJ1ozLMulAggTzN70drf/DlD4GfcwndqS8iO/H7MmkeU51fbigwJLPqM8lXQDCbxb0ZHrxmQ+hFiU="},
+	{"N2zAE5YnGLAr7vJa2vpgbNE929ukFCz", "+32dAN4MBat5gVnpSI2qIMr14dDhGasidvL++ShiqMA=", "BPaI3yWUiScguksTFY/210aMtdX/Bxth05JeE86/FdwMK6tJwlmvp2dPbQUfPDUa+X7MYB7Lto8cQwvznAptTR0="},
+	{"N3wrVVsHCdific6bRuKqS7QW8ZiNhFP", "70I7lMROlgfo4ozCOVT/LIHamL/iR+dFjdShPbqmDCY=", "BPQuLoGxjFs0jJ5CpNxfiVoIoqvcZ/A3Rwna3IYsu+hbvlx546q9xG5vY8FtAsESUU5D6Q5mey5SIVvIqyrpYH4="},
+}
+
+func TestTestKeys(t *testing.T) {
+	for _, k := range cTestKeys {
+		if !utils.IsValidAddress(k.Address) {
+			t.Errorf("%s: invalid address", k.Address)
+		}
+		if a := utils.AddressFromPublicKey(k.PublicKey); a != k.Address {
+			t.Errorf("%s: public key derives %s", k.Address, a)
+		}
+		signature, err := utils.SignMessage("QUJD", k.PrivateKey)
+		if err != nil || !utils.VerifyMessage("QUJD", signature, k.PublicKey) {
+			t.Errorf("%s: private key does not match the public key", k.Address)
+		}
+	}
+}
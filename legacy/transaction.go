@@ -4,6 +4,12 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	cEmptyReference = "null"
 )
 
 type LegacyTransaction struct {
@@ -23,6 +29,55 @@ type LegacyTransaction struct {
 	TransferID      PascalShortString // Capacity 64
 }
 
+// NewLegacyTransaction creates a transaction with its strings allocated
+func NewLegacyTransaction() *LegacyTransaction {
+	return &LegacyTransaction{
+		OrderID:    *NewPascalShortString(64),
+		OrderType:  *NewPascalShortString(6),
+		Reference:  *NewPascalShortString(64),
+		Sender:     *NewPascalShortString(120),
+		Address:    *NewPascalShortString(40),
+		Receiver:   *NewPascalShortString(40),
+		Signature:  *NewPascalShortString(120),
+		TransferID: *NewPascalShortString(64),
+	}
+}
+
+// AsProtocolString returns the transaction as an order line of the node protocol
+func (t *LegacyTransaction) AsProtocolString() string {
+	reference := t.Reference.GetString()
+	if reference == "" {
+		reference = cEmptyReference
+	}
+
+	return strings.Join([]string{
+		t.OrderType.GetString(),
+		t.OrderID.GetString(),
+		strconv.FormatInt(int64(t.OrderLinesCount), 10),
+		t.OrderType.GetString(),
+		strconv.FormatInt(t.TimeStamp, 10),
+		reference,
+		strconv.FormatInt(int64(t.TransferIndex), 10),
+		t.Sender.GetString(),
+		t.Address.GetString(),
+		t.Receiver.GetString(),
+		strconv.FormatInt(t.AmountFee, 10),
+		strconv.FormatInt(t.AmountTransfer, 10),
+		t.Signature.GetString(),
+		t.TransferID.GetString(),
+	}, " ")
+}
+
+// signatureMessage returns the text signed by the sender of a transfer line
+func (t *LegacyTransaction) signatureMessage() string {
+	return strconv.FormatInt(t.TimeStamp, 10) +
+		t.Address.GetString() +
+		t.Receiver.GetString() +
+		strconv.FormatInt(t.AmountTransfer, 10) +
+		strconv.FormatInt(t.AmountFee, 10) +
+		strconv.FormatInt(int64(t.TransferIndex), 10)
+}
+
 // ReadFromStream reads a transaction from a stream
 func (t *LegacyTransaction) ReadFromStream(r io.Reader) error {
 	// Check if the stream is nil
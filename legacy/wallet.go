@@ -2,7 +2,6 @@ package legacy
 
 import (
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -28,9 +27,19 @@ func (w *LegacyWallet) ReadFromFile(f string) error {
 	}
 	defer file.Close()
 
+	return w.ReadFromStream(file)
+}
+
+// ReadFromStream reads the wallet accounts from a stream
+func (w *LegacyWallet) ReadFromStream(r io.Reader) error {
+	// Check if the stream is nil
+	if r == nil {
+		return errors.New("nil reader provided")
+	}
+
 	for {
 		a := LegacyWalletAccount{}
-		err := a.ReadFromStream(file)
+		err := a.ReadFromStream(r)
 		if err == io.EOF {
 			break
 		}
@@ -44,18 +53,143 @@ func (w *LegacyWallet) ReadFromFile(f string) error {
 	return nil
 }
 
+// WriteToFile writes the wallet in the layout the Pascal wallet opens. The
+// file holds private keys, so it is only readable by the owner, and it is
+// replaced atomically.
+func (w *LegacyWallet) WriteToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0600, w.WriteToStream)
+}
+
+// WriteToFileWithBackup copies the existing wallet file to a .bak file, then
+// writes the wallet over it
+func (w *LegacyWallet) WriteToFileWithBackup(f string) error {
+	if utils.FileExists(f) {
+		_, err := utils.BackupFile(f)
+		if err != nil {
+			return fmt.Errorf("cannot back up wallet: %s", err)
+		}
+	}
+	return w.WriteToFile(f)
+}
+
+// WriteToStream writes the wallet accounts to a stream
+func (w *LegacyWallet) WriteToStream(wr io.Writer) error {
+	// Check if the stream is nil
+	if wr == nil {
+		return errors.New("nil writer provided")
+	}
+
+	for i := range w.Accounts {
+		err := w.Accounts[i].WriteToStream(wr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddAccount appends an account, rejecting addresses already in the wallet
+func (w *LegacyWallet) AddAccount(a LegacyWalletAccount) error {
+	address := a.Hash.GetString()
+	if w.IndexOf(address) >= 0 {
+		return fmt.Errorf("address %s already in the wallet", address)
+	}
+	w.Accounts = append(w.Accounts, a)
+	w.AccountsCount = int64(len(w.Accounts))
+	return nil
+}
+
+// GenerateAccount creates an account with a new key pair and adds it to the wallet
+func (w *LegacyWallet) GenerateAccount() (*LegacyWalletAccount, error) {
+	a, err := NewLegacyWalletAccount()
+	if err != nil {
+		return nil, err
+	}
+	err = w.AddAccount(*a)
+	if err != nil {
+		return nil, err
+	}
+	return &w.Accounts[len(w.Accounts)-1], nil
+}
+
+// RemoveAccount removes the account with the given address
+func (w *LegacyWallet) RemoveAccount(address string) error {
+	i := w.IndexOf(address)
+	if i < 0 {
+		return fmt.Errorf("address %s not in the wallet", address)
+	}
+	w.Accounts = append(w.Accounts[:i], w.Accounts[i+1:]...)
+	w.AccountsCount = int64(len(w.Accounts))
+	return nil
+}
+
+// RenameAccount sets the custom alias of the account with the given address
+func (w *LegacyWallet) RenameAccount(address, custom string) error {
+	i := w.IndexOf(address)
+	if i < 0 {
+		return fmt.Errorf("address %s not in the wallet", address)
+	}
+	return w.Accounts[i].Custom.SetString(custom)
+}
+
+// IndexOf returns the position of the account with the given address, or -1
+func (w *LegacyWallet) IndexOf(address string) int {
+	for i := range w.Accounts {
+		if w.Accounts[i].Hash.GetString() == address {
+			return i
+		}
+	}
+	return -1
+}
+
 type LegacyWalletAccount struct {
-	Hash          PascalShortString `json:"hash"`        // Capacity 40
-	Custom        PascalShortString `json:"custom"`      // Capacity 40
-	PrivateKey    PascalShortString `json:"private-key"` // Capacity 255
-	PublicKey     PascalShortString `json:"public-key"`  // Capacity 255
+	Hash          PascalShortString `json:"hash"`                         // Capacity 40
+	Custom        PascalShortString `json:"custom"`                       // Capacity 40
+	PrivateKey    PascalShortString `json:"private-key" sensitive:"true"` // Capacity 255
+	PublicKey     PascalShortString `json:"public-key"`                   // Capacity 255
 	Balance       int64             `json:"balance"`
 	Pending       int64             `json:"pending"`
 	Score         int64             `json:"score"`
 	LastOperation int64             `json:"last-operation"`
 }
 
-func (a *LegacyWalletAccount) ReadFromStream(f *os.File) error {
+// NewLegacyWalletAccount creates an account with a new key pair
+func NewLegacyWalletAccount() (*LegacyWalletAccount, error) {
+	privateKey, publicKey, err := utils.NewKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return NewLegacyWalletAccountFromKeys(publicKey, privateKey)
+}
+
+// NewLegacyWalletAccountFromKeys creates an account from existing base64 keys,
+// deriving its address from the public key
+func NewLegacyWalletAccountFromKeys(publicKey, privateKey string) (*LegacyWalletAccount, error) {
+	a := &LegacyWalletAccount{
+		Hash:       *NewPascalShortString(40),
+		Custom:     *NewPascalShortString(40),
+		PublicKey:  *NewPascalShortString(255),
+		PrivateKey: *NewPascalShortString(255),
+	}
+
+	err := a.Hash.SetString(utils.AddressFromPublicKey(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	err = a.PublicKey.SetString(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	err = a.PrivateKey.SetString(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *LegacyWalletAccount) ReadFromStream(f io.Reader) error {
 	// Check if the stream is nil
 	if f == nil {
 		return errors.New("nil reader provided")
@@ -116,11 +250,39 @@ func (a *LegacyWalletAccount) ReadFromStream(f *os.File) error {
 	return nil
 }
 
-func (w *LegacyWallet) AsJSON() string {
-	jsonData, err := json.MarshalIndent(w, "", "  ")
-	if err != nil {
-		fmt.Printf("error %v", err)
-		return ""
+// WriteToStream writes the account record to a stream
+func (a *LegacyWalletAccount) WriteToStream(w io.Writer) error {
+	// Check if the stream is nil
+	if w == nil {
+		return errors.New("nil writer provided")
+	}
+
+	// Fields Hash, Custom, PublicKey and PrivateKey
+	for _, p := range []*PascalShortString{&a.Hash, &a.Custom, &a.PublicKey, &a.PrivateKey} {
+		err := p.WriteToStream(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Fields Balance, Pending, Score and LastOperation
+	for _, v := range []int64{a.Balance, a.Pending, a.Score, a.LastOperation} {
+		err := binary.Write(w, binary.LittleEndian, v)
+		if err != nil {
+			return err
+		}
 	}
-	return string(jsonData)
+
+	return nil
+}
+
+// AsJSON renders the wallet as JSON with the private keys redacted
+func (w *LegacyWallet) AsJSON() string {
+	return w.AsJSONWithOptions(RenderOptions{})
+}
+
+// AsJSONWithOptions renders the wallet as JSON, showing the private keys
+// only when the options allow secrets
+func (w *LegacyWallet) AsJSONWithOptions(opts RenderOptions) string {
+	return renderJSON(w, opts)
 }
@@ -0,0 +1,70 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+func TestWalletRoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var w LegacyWallet
+	err = w.ReadFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.AccountsCount != 2 {
+		t.Fatalf("accounts: got %d, want 2", w.AccountsCount)
+	}
+	for _, a := range w.Accounts {
+		if utils.AddressFromPublicKey(a.PublicKey.GetString()) != a.Hash.GetString() {
+			t.Errorf("%s: address does not match the public key", a.Hash.GetString())
+		}
+	}
+
+	var out bytes.Buffer
+	err = w.WriteToStream(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Error("written wallet differs from the original file")
+	}
+}
+
+func TestWalletWriteToFile(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := filepath.Join(t.TempDir(), "wallet.pkw")
+	err = os.WriteFile(f, []byte("previous"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteToFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("permissions: got %o, want 600", perm)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(f))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %d entries", len(entries))
+	}
+}
@@ -0,0 +1,95 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+// LegacyWalletAuditReport lists the problems found in one wallet account
+type LegacyWalletAuditReport struct {
+	Position int      `json:"position"`
+	Address  string   `json:"address"`
+	Issues   []string `json:"issues"`
+}
+
+// OK reports whether the account passed every check
+func (r *LegacyWalletAuditReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Audit checks that every account key pair and address belong together and
+// that no account is duplicated. It returns one report per account.
+func (w *LegacyWallet) Audit() []LegacyWalletAuditReport {
+	reports := make([]LegacyWalletAuditReport, len(w.Accounts))
+	addresses := make(map[string]int)
+	publicKeys := make(map[string]int)
+
+	for i := range w.Accounts {
+		a := &w.Accounts[i]
+		r := &reports[i]
+		r.Position = i
+		r.Address = a.Hash.GetString()
+		add := func(format string, args ...any) {
+			r.Issues = append(r.Issues, fmt.Sprintf(format, args...))
+		}
+
+		address := a.Hash.GetString()
+		publicKey := a.PublicKey.GetString()
+		privateKey := a.PrivateKey.GetString()
+
+		// Clamped length bytes
+		if a.Hash.Clamped() || a.Custom.Clamped() || a.PublicKey.Clamped() || a.PrivateKey.Clamped() {
+			add("length byte exceeded the field capacity")
+		}
+
+		// Duplicates
+		if first, ok := addresses[address]; ok {
+			add("address already present at position %d", first)
+		} else {
+			addresses[address] = i
+		}
+		if first, ok := publicKeys[publicKey]; ok {
+			add("public key already present at position %d", first)
+		} else {
+			publicKeys[publicKey] = i
+		}
+
+		// Address
+		if !utils.IsValidAddress(address) {
+			add("address checksum does not match")
+		}
+
+		// Public key
+		err := utils.ValidatePublicKey(publicKey)
+		if err != nil {
+			add("malformed public key: %s", err)
+		} else if derived := utils.AddressFromPublicKey(publicKey); derived != address {
+			add("public key derives address %s", derived)
+		}
+
+		// Private key
+		if a.IsWatchOnly() {
+			continue
+		}
+		derived, err := utils.PublicKeyFromPrivateKey(privateKey)
+		if err != nil {
+			add("malformed private key: %s", err)
+		} else if derived != publicKey {
+			add("private key does not derive the public key")
+		}
+	}
+
+	return reports
+}
+
+// AuditAsJSON renders the audit reports as JSON
+func (w *LegacyWallet) AuditAsJSON() string {
+	jsonData, err := json.MarshalIndent(w.Audit(), "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
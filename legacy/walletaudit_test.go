@@ -0,0 +1,61 @@
+package legacy
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWalletAudit(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range w.Audit() {
+		if !r.OK() {
+			t.Errorf("%s: got %q", r.Address, r.Issues)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *LegacyWalletAccount)
+		issue  string
+	}{
+		{"public key of another address", func(a *LegacyWalletAccount) {
+			a.PublicKey.SetString(cTestKeys[2].PublicKey)
+		}, "public key derives address " + cTestKeys[2].Address},
+		{"private key of another address", func(a *LegacyWalletAccount) {
+			a.PrivateKey.SetString(cTestKeys[2].PrivateKey)
+		}, "private key does not derive the public key"},
+		{"mistyped address", func(a *LegacyWalletAccount) {
+			a.Hash.SetString(strings.Replace(a.Hash.GetString(), "B", "C", 1))
+		}, "address checksum does not match"},
+	}
+	for _, tt := range tests {
+		w := LegacyWallet{}
+		err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tt.change(&w.Accounts[0])
+
+		reports := w.Audit()
+		if !reports[1].OK() {
+			t.Errorf("%s: untouched account flagged: %q", tt.name, reports[1].Issues)
+		}
+		found := false
+		for _, issue := range reports[0].Issues {
+			found = found || issue == tt.issue
+		}
+		if !found {
+			t.Errorf("%s: got %q, want %q", tt.name, reports[0].Issues, tt.issue)
+		}
+	}
+
+	w.Accounts = append(w.Accounts, w.Accounts[0])
+	if r := w.Audit()[2]; len(r.Issues) != 2 {
+		t.Errorf("duplicate: got %q, want the address and the public key", r.Issues)
+	}
+}
@@ -0,0 +1,186 @@
+package legacy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Friends-Of-Noso/NosoData-Go/utils"
+)
+
+const (
+	cKeysCommentChar = "#"
+)
+
+// IsWatchOnly reports whether the account has no private key
+func (a *LegacyWalletAccount) IsWatchOnly() bool {
+	return a.PrivateKey.GetString() == ""
+}
+
+// ImportKeysFromFile imports the accounts of a text key file
+func (w *LegacyWallet) ImportKeysFromFile(f string) (int, error) {
+	// Check if the file exists before trying to open it
+	if !utils.FileExists(f) {
+		return 0, fmt.Errorf("file %s not found", f)
+	}
+
+	file, err := os.Open(f)
+	if err != nil {
+		return 0, fmt.Errorf("cannot open file: %s", err)
+	}
+	defer file.Close()
+
+	return w.ImportKeys(file)
+}
+
+// ImportKeys imports accounts from text lines holding a public key followed
+// by its private key, as used by the Pascal wallet, or a public key alone
+// for watch-only accounts. Empty lines and lines starting with # are ignored,
+// and accounts already in the wallet are skipped. It returns the number of
+// accounts added.
+func (w *LegacyWallet) ImportKeys(r io.Reader) (int, error) {
+	// Check if the stream is nil
+	if r == nil {
+		return 0, errors.New("nil reader provided")
+	}
+
+	added := 0
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, cKeysCommentChar) {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) > 2 {
+			return added, fmt.Errorf("line %d: expected public and private key", line)
+		}
+		publicKey := fields[0]
+		privateKey := ""
+		if len(fields) == 2 {
+			privateKey = fields[1]
+		}
+
+		err := utils.ValidatePublicKey(publicKey)
+		if err != nil {
+			return added, fmt.Errorf("line %d: %s", line, err)
+		}
+		if privateKey != "" {
+			derived, err := utils.PublicKeyFromPrivateKey(privateKey)
+			if err != nil {
+				return added, fmt.Errorf("line %d: %s", line, err)
+			}
+			if derived != publicKey {
+				return added, fmt.Errorf("line %d: private key does not derive the public key", line)
+			}
+		}
+
+		a, err := NewLegacyWalletAccountFromKeys(publicKey, privateKey)
+		if err != nil {
+			return added, fmt.Errorf("line %d: %s", line, err)
+		}
+		if w.mergeAccount(*a) {
+			added++
+		}
+	}
+
+	return added, scanner.Err()
+}
+
+// ExportKeysToFile writes the account keys to a text key file readable by
+// the owner only, replacing any existing file
+func (w *LegacyWallet) ExportKeysToFile(f string) error {
+	return utils.WriteFileAtomic(f, 0600, w.ExportKeys)
+}
+
+// ExportKeys writes one line per account with its public and private keys,
+// or only the public key for watch-only accounts
+func (w *LegacyWallet) ExportKeys(wr io.Writer) error {
+	// Check if the stream is nil
+	if wr == nil {
+		return errors.New("nil writer provided")
+	}
+
+	for i := range w.Accounts {
+		a := &w.Accounts[i]
+		line := a.PublicKey.GetString()
+		if !a.IsWatchOnly() {
+			line += " " + a.PrivateKey.GetString()
+		}
+		_, err := fmt.Fprintln(wr, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Merge adds the accounts of other that are not in the wallet yet. Watch-only
+// accounts get the private key when other has it. It returns the number of
+// accounts added.
+func (w *LegacyWallet) Merge(other *LegacyWallet) int {
+	added := 0
+	for i := range other.Accounts {
+		if w.mergeAccount(other.Accounts[i]) {
+			added++
+		}
+	}
+	return added
+}
+
+// MergeWalletFiles reads the wallet files and merges them into one wallet,
+// skipping duplicated addresses
+func MergeWalletFiles(files ...string) (*LegacyWallet, error) {
+	merged := &LegacyWallet{}
+	for _, f := range files {
+		w := LegacyWallet{}
+		err := w.ReadFromFile(f)
+		if err != nil {
+			return nil, err
+		}
+		merged.Merge(&w)
+	}
+	return merged, nil
+}
+
+// WatchOnly returns a copy of the wallet with addresses and public keys but
+// without private keys
+func (w *LegacyWallet) WatchOnly() *LegacyWallet {
+	watch := &LegacyWallet{
+		AccountsCount: w.AccountsCount,
+		Accounts:      make([]LegacyWalletAccount, len(w.Accounts)),
+	}
+	for i, a := range w.Accounts {
+		a.Hash = a.Hash.Clone()
+		a.Custom = a.Custom.Clone()
+		a.PublicKey = a.PublicKey.Clone()
+		// A fresh string so no bytes of the key survive as garbage
+		a.PrivateKey = *NewPascalShortString(255)
+		watch.Accounts[i] = a
+	}
+	return watch
+}
+
+// mergeAccount adds the account, or completes the private key of a
+// watch-only copy already in the wallet. It reports whether it was added.
+func (w *LegacyWallet) mergeAccount(a LegacyWalletAccount) bool {
+	i := w.IndexOf(a.Hash.GetString())
+	if i < 0 {
+		w.Accounts = append(w.Accounts, a)
+		w.AccountsCount = int64(len(w.Accounts))
+		return true
+	}
+
+	existing := &w.Accounts[i]
+	if existing.IsWatchOnly() && !a.IsWatchOnly() && existing.PublicKey.GetString() == a.PublicKey.GetString() {
+		existing.PrivateKey = a.PrivateKey.Clone()
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWalletKeysRoundTrip(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, err := NewLegacyWalletAccountFromKeys(cTestKeys[2].PublicKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.AddAccount(*watch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An existing file is replaced and made private
+	f := filepath.Join(t.TempDir(), "keys.txt")
+	err = os.WriteFile(f, []byte("old contents\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.ExportKeysToFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("permissions: got %o, want 600", perm)
+	}
+
+	var imported LegacyWallet
+	added, err := imported.ImportKeysFromFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 3 || len(imported.Accounts) != 3 {
+		t.Fatalf("added: got %d, want 3", added)
+	}
+	for i := range w.Accounts {
+		a, b := &w.Accounts[i], &imported.Accounts[i]
+		if a.Hash.GetString() != b.Hash.GetString() || a.PublicKey.GetString() != b.PublicKey.GetString() || a.PrivateKey.GetString() != b.PrivateKey.GetString() {
+			t.Errorf("account %d: got %s, want %s", i, b.Hash.GetString(), a.Hash.GetString())
+		}
+	}
+	if !imported.Accounts[2].IsWatchOnly() {
+		t.Error("watch-only account imported with a private key")
+	}
+
+	// Importing again adds nothing
+	added, err = imported.ImportKeysFromFile(f)
+	if err != nil || added != 0 {
+		t.Errorf("import again: added %d, %v", added, err)
+	}
+}
+
+func TestWalletImportKeysMismatch(t *testing.T) {
+	var w LegacyWallet
+	_, err := w.ImportKeys(strings.NewReader(cTestKeys[0].PublicKey + " " + cTestKeys[1].PrivateKey + "\n"))
+	if err == nil {
+		t.Error("expected an error for a private key of another public key")
+	}
+}
+
+func TestWalletMerge(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watch := w.WatchOnly()
+	if added := watch.Merge(&w); added != 0 || len(watch.Accounts) != 2 {
+		t.Errorf("duplicates: added %d, got %d accounts", added, len(watch.Accounts))
+	}
+	// The watch-only copies get back their private keys
+	for i := range watch.Accounts {
+		if watch.Accounts[i].PrivateKey.GetString() != w.Accounts[i].PrivateKey.GetString() {
+			t.Errorf("%s: private key not merged", watch.Accounts[i].Hash.GetString())
+		}
+	}
+
+	other := LegacyWallet{}
+	a, err := NewLegacyWalletAccountFromKeys(cTestKeys[2].PublicKey, cTestKeys[2].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = other.AddAccount(*a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added := w.Merge(&other); added != 1 || w.AccountsCount != 3 {
+		t.Errorf("merge: added %d, got %d accounts", added, w.AccountsCount)
+	}
+}
+
+func TestWalletWatchOnly(t *testing.T) {
+	var w LegacyWallet
+	err := w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watch := w.WatchOnly()
+	for i := range watch.Accounts {
+		a := &watch.Accounts[i]
+		var raw bytes.Buffer
+		err = a.PrivateKey.WriteToStream(&raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// No garbage bytes of the key are written either
+		if !a.IsWatchOnly() || bytes.Contains(raw.Bytes(), []byte(w.Accounts[i].PrivateKey.GetString()[:8])) {
+			t.Errorf("%s: private key kept", a.Hash.GetString())
+		}
+		if a.PublicKey.GetString() != w.Accounts[i].PublicKey.GetString() {
+			t.Errorf("%s: public key changed", a.Hash.GetString())
+		}
+	}
+	if w.Accounts[0].IsWatchOnly() {
+		t.Error("the original wallet lost its private keys")
+	}
+
+	var out strings.Builder
+	err = watch.ExportKeys(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), cTestKeys[0].PrivateKey) {
+		t.Error("watch-only export holds a private key")
+	}
+}
@@ -0,0 +1,98 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// LegacyReconcileDiff is a wallet cached value that differs from the summary
+type LegacyReconcileDiff struct {
+	Position int    `json:"position"`
+	Address  string `json:"address"`
+	Field    string `json:"field"`
+	Wallet   string `json:"wallet"`
+	Summary  string `json:"summary"`
+}
+
+// String returns a human readable description of the difference
+func (d LegacyReconcileDiff) String() string {
+	return fmt.Sprintf("position %d (%s): %s is '%s', summary has '%s'", d.Position, d.Address, d.Field, d.Wallet, d.Summary)
+}
+
+// Reconcile compares the cached values of every wallet account against the
+// summary. Accounts missing from the summary are compared against an empty
+// account. Pending is not part of the summary and is not compared.
+func (w *LegacyWallet) Reconcile(s *LegacySummary) []LegacyReconcileDiff {
+	var diffs []LegacyReconcileDiff
+
+	for i := range w.Accounts {
+		a := &w.Accounts[i]
+		address := a.Hash.GetString()
+		sa := s.AccountByAddress(address)
+		if sa == nil {
+			sa = &LegacySummaryAccount{Custom: *NewPascalShortString(40)}
+		}
+
+		add := func(field, wallet, summary string) {
+			if wallet == summary {
+				return
+			}
+			diffs = append(diffs, LegacyReconcileDiff{
+				Position: i,
+				Address:  address,
+				Field:    field,
+				Wallet:   wallet,
+				Summary:  summary,
+			})
+		}
+
+		add("custom", a.Custom.GetString(), sa.Custom.GetString())
+		add("balance", strconv.FormatInt(a.Balance, 10), strconv.FormatInt(sa.Balance, 10))
+		add("score", strconv.FormatInt(a.Score, 10), strconv.FormatInt(sa.Score, 10))
+		add("last-operation", strconv.FormatInt(a.LastOperation, 10), strconv.FormatInt(sa.LastOperation, 10))
+	}
+
+	return diffs
+}
+
+// ApplyReconcile overwrites the cached values of the wallet accounts with the
+// summary ones and returns the number of accounts changed
+func (w *LegacyWallet) ApplyReconcile(s *LegacySummary) (int, error) {
+	changed := make(map[int]bool)
+
+	for _, d := range w.Reconcile(s) {
+		a := &w.Accounts[d.Position]
+		sa := s.AccountByAddress(d.Address)
+		if sa == nil {
+			sa = &LegacySummaryAccount{Custom: *NewPascalShortString(40)}
+		}
+
+		switch d.Field {
+		case "custom":
+			err := a.Custom.SetString(sa.Custom.GetString())
+			if err != nil {
+				return len(changed), err
+			}
+		case "balance":
+			a.Balance = sa.Balance
+		case "score":
+			a.Score = sa.Score
+		case "last-operation":
+			a.LastOperation = sa.LastOperation
+		}
+		changed[d.Position] = true
+	}
+
+	return len(changed), nil
+}
+
+// ReconcileAsJSON renders the differences with the summary as JSON
+func (w *LegacyWallet) ReconcileAsJSON(s *LegacySummary) string {
+	jsonData, err := json.MarshalIndent(w.Reconcile(s), "", "  ")
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return ""
+	}
+	return string(jsonData)
+}
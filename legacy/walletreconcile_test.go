@@ -0,0 +1,125 @@
+package legacy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testReconciledWallet returns the testdata summary and a copy of the testdata
+// wallet with the summary values
+func testReconciledWallet(t *testing.T) (*LegacySummary, *LegacyWallet) {
+	t.Helper()
+
+	var s LegacySummary
+	err := s.ReadFromFile(filepath.Join("testdata", "sumary.psk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w LegacyWallet
+	err = w.ReadFromFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.ApplyReconcile(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &s, &w
+}
+
+func TestWalletReconcile(t *testing.T) {
+	s, w := testReconciledWallet(t)
+	if diffs := w.Reconcile(s); len(diffs) != 0 {
+		t.Fatalf("reconciled wallet: got %v", diffs)
+	}
+
+	w.Accounts[1].Balance++
+	w.Accounts[1].Custom.SetString("savings")
+	diffs := w.Reconcile(s)
+	if len(diffs) != 2 {
+		t.Fatalf("diffs: got %v", diffs)
+	}
+	for _, d := range diffs {
+		if d.Position != 1 || d.Address != w.Accounts[1].Hash.GetString() {
+			t.Errorf("diff: got %s", d)
+		}
+	}
+	if diffs[0].Field != "custom" || diffs[0].Wallet != "savings" || diffs[0].Summary != "payouts" {
+		t.Errorf("custom: got %s", diffs[0])
+	}
+	if diffs[1].Field != "balance" || diffs[1].Wallet != "123456790" || diffs[1].Summary != "123456789" {
+		t.Errorf("balance: got %s", diffs[1])
+	}
+
+	n, err := w.ApplyReconcile(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || w.Accounts[1].Balance != 123456789 || w.Accounts[1].Custom.GetString() != "payouts" {
+		t.Errorf("apply: changed %d, got %+v", n, w.Accounts[1])
+	}
+}
+
+func TestWalletApplyReconcileMatch(t *testing.T) {
+	s, w := testReconciledWallet(t)
+
+	var before, after bytes.Buffer
+	err := w.WriteToStream(&before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := w.ApplyReconcile(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteToStream(&after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 || !bytes.Equal(before.Bytes(), after.Bytes()) {
+		t.Errorf("matching wallet changed: %d accounts", n)
+	}
+}
+
+func TestWalletWriteToFileWithBackup(t *testing.T) {
+	original, err := os.ReadFile(filepath.Join("testdata", "wallet.pkw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := filepath.Join(t.TempDir(), "wallet.pkw")
+	err = os.WriteFile(f, original, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, w := testReconciledWallet(t)
+	err = w.WriteToFileWithBackup(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := os.ReadFile(f + ".bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(backup, original) {
+		t.Error("backup differs from the wallet before the overwrite")
+	}
+	var written LegacyWallet
+	err = written.ReadFromFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := written.Reconcile(s); len(diffs) != 0 {
+		t.Errorf("written wallet: got %v", diffs)
+	}
+	data, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(data, original) {
+		t.Error("the wallet was not overwritten")
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -16,6 +18,9 @@ const (
 	cSummaryFilename = "sumary.psk"
 	cGVTFilename     = "gvts.psk"
 	cPSOFilename     = "psos.dat"
+	cHeadersFilename = "blchhead.nos"
+	cMNsFilename     = "masternodes.txt"
+	cConfigFilename  = "nosocfg.psk"
 )
 
 var (
@@ -24,11 +29,47 @@ var (
 	summary legacy.LegacySummary
 	gvts    legacy.LegacyGVT
 	psos    legacy.LegacyPSO
+	hashes  legacy.ConsensusSnapshot
+	headers legacy.LegacyHeaders
+	mns     legacy.LegacyMasternodes
+	config  legacy.LegacyConfig
+
+	dataDir *legacy.LegacyDataDir // nil reads the flat test data folder
+
+	renderOptions legacy.RenderOptions
 )
 
+// dataFile returns the path of a data file in the data dir, or in the test
+// data folder when no data dir was given
+func dataFile(fromDataDir func(*legacy.LegacyDataDir) string, testFilename string) string {
+	if dataDir == nil {
+		return filepath.Join(cTestDataFolder, testFilename)
+	}
+	return fromDataDir(dataDir)
+}
+
+// blockFilename returns the path of the last block in the data dir, or of the
+// test block when no data dir was given
+func blockFilename() (string, error) {
+	if dataDir == nil {
+		return filepath.Join(cTestDataFolder, cBlockFilename), nil
+	}
+	store := dataDir.BlockStore()
+	last, err := store.LastBlock()
+	if err != nil {
+		return "", err
+	}
+	return store.BlockFilename(last), nil
+}
+
 func displayBlock(jsonOutput bool) {
 	fmt.Printf("\n%s\n", "== Block ==")
-	err := block.ReadFromFile(filepath.Join(cTestDataFolder, cBlockFilename))
+	filename, err := blockFilename()
+	if err != nil {
+		fmt.Printf("error %v", err)
+		return
+	}
+	err = block.ReadFromFile(filename)
 	if err != nil {
 		fmt.Printf("error %v", err)
 		return
@@ -96,45 +137,77 @@ func displayBlock(jsonOutput bool) {
 		} else {
 			fmt.Println("No MN rewards")
 		}
+
+		changes := block.BalanceChanges()
+		fmt.Printf("Balance changes(%d):\n", len(changes))
+		for _, c := range changes {
+			fmt.Printf("  Address: '%s'\n", c.Address)
+			fmt.Println("      Amount:", utils.ToNoso(c.Amount))
+			fmt.Println("      Cause: ", c.Cause)
+		}
+
+		for _, issue := range block.VerifyFees() {
+			fmt.Println("Fee issue:", issue)
+		}
 	}
 }
 
 func displayWallet(jsonOutput bool) {
 	// Wallet
 	fmt.Printf("\n%s\n", "== Wallet ==")
-	err := wallet.ReadFromFile(filepath.Join(cTestDataFolder, cWalletFilename))
+	err := wallet.ReadFromFile(dataFile((*legacy.LegacyDataDir).WalletFilename, cWalletFilename))
 	if err != nil {
 		fmt.Println("error reading wallet:", err)
 		return
 	}
 	if jsonOutput {
-		fmt.Println(wallet.AsJSON())
+		fmt.Println(wallet.AsJSONWithOptions(renderOptions))
 	} else {
 		for i, a := range wallet.Accounts {
 			fmt.Println("Position:", i)
 			fmt.Printf("    Hash: '%s'\n", a.Hash.GetString())
 			fmt.Printf("    Custom:         '%s'\n", a.Custom.GetString())
 			fmt.Printf("    Pub key:        '%s'\n", a.PublicKey.GetString())
-			fmt.Printf("    Priv key:       '%s'\n", a.PrivateKey.GetString())
+			fmt.Printf("    Priv key:       '%s'\n", renderOptions.Redact(a.PrivateKey.GetString()))
 			fmt.Println("    Balance:       ", utils.ToNoso(a.Balance))
 			fmt.Println("    Pending:       ", utils.ToNoso(a.Pending))
 			fmt.Println("    Score:         ", utils.ToNoso(a.Score))
 			fmt.Println("    Last Operation:", utils.ToNoso(a.LastOperation))
 		}
+
+		for _, r := range wallet.Audit() {
+			if r.OK() {
+				continue
+			}
+			fmt.Printf("Audit position %d '%s':\n", r.Position, r.Address)
+			for _, i := range r.Issues {
+				fmt.Println("   ", i)
+			}
+		}
 	}
 }
 
 func displaySummary(jsonOutput bool) {
 	// Summary
 	fmt.Printf("\n%s\n", "== Summary ==")
-	err := summary.ReadFromFile(filepath.Join(cTestDataFolder, cSummaryFilename))
+	err := summary.ReadFromFile(dataFile((*legacy.LegacyDataDir).SummaryFilename, cSummaryFilename))
 	if err != nil {
 		fmt.Println("error reading summary:", err)
 		return
 	}
+	var h legacy.LegacyHeaders
+	if h.ReadFromFile(dataFile((*legacy.LegacyDataDir).HeadersFilename, cHeadersFilename)) == nil {
+		summary.SetBlockFromHeaders(&h)
+	}
 	if jsonOutput {
 		fmt.Println(summary.AsJSON())
 	} else {
+		fmt.Printf("Hash:    '%s'\n", summary.Hash)
+		if summary.Block >= 0 {
+			fmt.Println("Block:  ", summary.Block)
+		} else {
+			fmt.Println("Block:   unknown")
+		}
 		for i, a := range summary.Accounts {
 			fmt.Println("Position:", i)
 			fmt.Printf("    Hash:           '%s'\n", a.Hash.GetString())
@@ -143,6 +216,16 @@ func displaySummary(jsonOutput bool) {
 			fmt.Println("    Score:         ", utils.ToNoso(a.Score))
 			fmt.Println("    Last Operation:", utils.ToNoso(a.LastOperation))
 		}
+
+		issues := summary.Validate(-1)
+		if len(issues) > 0 {
+			fmt.Printf("Issues(%d):\n", len(issues))
+			for _, i := range issues {
+				fmt.Println("  ", i)
+			}
+		} else {
+			fmt.Println("No issues")
+		}
 	}
 
 }
@@ -150,7 +233,7 @@ func displaySummary(jsonOutput bool) {
 func displayGVT(jsonOutput bool) {
 	// GVT
 	fmt.Printf("\n%s\n", "== GVT ==")
-	err := gvts.ReadFromFile(filepath.Join(cTestDataFolder, cGVTFilename))
+	err := gvts.ReadFromFile(dataFile((*legacy.LegacyDataDir).GVTFilename, cGVTFilename))
 	if err != nil {
 		fmt.Println("error reading GVT:", err)
 		return
@@ -165,13 +248,16 @@ func displayGVT(jsonOutput bool) {
 			fmt.Printf("    Hash:    '%s'\n", e.Hash.GetString())
 			fmt.Println("    Control:", e.Control)
 		}
+		for _, issue := range gvts.Validate() {
+			fmt.Println("Issue:", issue)
+		}
 	}
 }
 
 func displayPSO(jsonOutput bool) {
 	// PSO
 	fmt.Printf("\n%s\n", "== PSO ==")
-	err := psos.ReadFromFile(filepath.Join(cTestDataFolder, cPSOFilename))
+	err := psos.ReadFromFile(dataFile((*legacy.LegacyDataDir).PSOFilename, cPSOFilename))
 	if err != nil {
 		fmt.Println("error reading PSO:", err)
 		return
@@ -184,22 +270,224 @@ func displayPSO(jsonOutput bool) {
 		for i, mli := range psos.MNLocks {
 			fmt.Println("  Position:", i)
 			fmt.Printf("      Address: '%s'\n", mli.Address.GetString())
-			fmt.Println("       Expire:", mli.Expire, "seconds")
+			fmt.Println("       Expire:", mli.Expire, "blocks, at block", mli.ExpiresAt(psos.Block))
 		}
 		fmt.Printf("  PSO Count(%d):\n", psos.PSOCount)
+		for i, pso := range psos.PSOS {
+			fmt.Println("  Position:", i)
+			fmt.Println("      Mode:   ", pso.Mode)
+			fmt.Printf("      Hash:    '%s'\n", pso.Hash)
+			fmt.Printf("      Owner:   '%s'\n", pso.Owner)
+			fmt.Println("      Expire: ", pso.Expire)
+			fmt.Printf("      Members: '%s'\n", pso.Members)
+			fmt.Printf("      Params:  '%s'\n", pso.Params)
+			for _, issue := range pso.Validate(psos.Block) {
+				fmt.Println("      Issue:  ", issue)
+			}
+		}
+	}
+
+}
+
+func displayHeaders(jsonOutput bool) {
+	// Headers
+	fmt.Printf("\n%s\n", "== Headers ==")
+	err := headers.ReadFromFile(dataFile((*legacy.LegacyDataDir).HeadersFilename, cHeadersFilename))
+	if err != nil {
+		fmt.Println("error reading headers:", err)
+		return
+	}
+	if jsonOutput {
+		fmt.Println(headers.AsJSON())
+	} else {
+		for _, h := range headers.Headers {
+			fmt.Println("Block:", h.Block)
+			fmt.Printf("    Block hash:   '%s'\n", h.BlockHash.GetString())
+			fmt.Printf("    Summary hash: '%s'\n", h.SummaryHash.GetString())
+		}
+	}
+}
+
+func displayMasternodes(jsonOutput bool) {
+	// Masternodes
+	fmt.Printf("\n%s\n", "== Masternodes ==")
+	err := mns.ReadFromFile(dataFile((*legacy.LegacyDataDir).MasternodesFilename, cMNsFilename))
+	if err != nil {
+		fmt.Println("error reading masternodes:", err)
+		return
+	}
+	if jsonOutput {
+		fmt.Println(mns.AsJSON())
+	} else {
+		fmt.Println("Block:", mns.Block)
+		fmt.Println("Nodes:", mns.NodesCount)
+		for _, n := range mns.Nodes {
+			fmt.Printf("    %s:%d\n", n.IP, n.Port)
+			fmt.Printf("      Sign:  '%s'\n", n.Sign)
+			fmt.Printf("      Fund:  '%s'\n", n.Fund)
+			fmt.Printf("      First: %d\n", n.First)
+			fmt.Printf("      Last:  %d\n", n.Last)
+		}
+		for _, issue := range mns.Validate() {
+			fmt.Println("Issue:", issue)
+		}
+		if block.Number == mns.Block+1 {
+			for _, issue := range mns.CrossCheckRewards(&block) {
+				fmt.Println("Reward issue:", issue)
+			}
+		}
 	}
+}
+
+func displayConfig(jsonOutput bool) {
+	// Config
+	fmt.Printf("\n%s\n", "== Config ==")
+	err := config.ReadFromFile(dataFile((*legacy.LegacyDataDir).ConfigFilename, cConfigFilename))
+	if err != nil {
+		fmt.Println("error reading config:", err)
+		return
+	}
+	if jsonOutput {
+		fmt.Println(config.AsJSONWithOptions(renderOptions))
+	} else {
+		fmt.Println("Port:         ", config.Port)
+		for _, n := range config.SeedNodes {
+			fmt.Printf("Seed node:     '%s'\n", n)
+		}
+		fmt.Printf("Miner address: '%s'\n", config.MinerAddress)
+		fmt.Printf("Pool:          '%s:%d'\n", config.PoolIP, config.PoolPort)
+		fmt.Printf("Pool password: '%s'\n", renderOptions.Redact(config.PoolPassword))
+		fmt.Printf("MN:            '%s:%d'\n", config.MNIP, config.MNPort)
+		fmt.Printf("MN sign:       '%s'\n", config.MNSign)
+		fmt.Printf("MN funds:      '%s'\n", config.MNFunds)
+		for _, e := range config.Extra {
+			fmt.Printf("%s: '%s'\n", e.Key, e.Value)
+		}
+		for _, issue := range config.Validate() {
+			fmt.Println("Issue:", issue)
+		}
+	}
+}
+
+func displayConsensus(jsonOutput bool) {
+	// Consensus
+	fmt.Printf("\n%s\n", "== Consensus ==")
+	err := hashes.ReadFromFiles(
+		dataFile((*legacy.LegacyDataDir).SummaryFilename, cSummaryFilename),
+		dataFile((*legacy.LegacyDataDir).GVTFilename, cGVTFilename),
+		dataFile((*legacy.LegacyDataDir).PSOFilename, cPSOFilename),
+		dataFile((*legacy.LegacyDataDir).HeadersFilename, cHeadersFilename),
+	)
+	if err != nil {
+		fmt.Println("error reading consensus hashes:", err)
+		return
+	}
+	if jsonOutput {
+		fmt.Println(hashes.AsJSON())
+	} else {
+		fmt.Printf("Summary: '%s'\n", hashes.SummaryHash)
+		fmt.Printf("GVT:     '%s'\n", hashes.GVTHash)
+		fmt.Printf("PSO:     '%s'\n", hashes.PSOHash)
+		fmt.Printf("Headers: '%s'\n", hashes.HeadersHash)
+	}
+}
 
+func displayFee(amount string) {
+	// Fee
+	n, err := utils.ParseNoso(amount)
+	if err != nil {
+		fmt.Println("error reading amount:", err)
+		return
+	}
+	fmt.Println("Amount:", utils.ToNoso(n))
+	fmt.Println("Fee:   ", utils.ToNoso(utils.GetFee(n)))
+	fmt.Println("Total: ", utils.ToNoso(n+utils.GetFee(n)))
+}
+
+func displayReconcile(jsonOutput, rewrite bool) {
+	// Reconcile
+	fmt.Printf("\n%s\n", "== Reconcile ==")
+	var w legacy.LegacyWallet
+	var s legacy.LegacySummary
+	walletFilename := dataFile((*legacy.LegacyDataDir).WalletFilename, cWalletFilename)
+	err := w.ReadFromFile(walletFilename)
+	if err != nil {
+		fmt.Println("error reading wallet:", err)
+		return
+	}
+	err = s.ReadFromFile(dataFile((*legacy.LegacyDataDir).SummaryFilename, cSummaryFilename))
+	if err != nil {
+		fmt.Println("error reading summary:", err)
+		return
+	}
+	if jsonOutput {
+		fmt.Println(w.ReconcileAsJSON(&s))
+	} else {
+		diffs := w.Reconcile(&s)
+		if len(diffs) == 0 {
+			fmt.Println("Wallet matches the summary")
+		}
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+	}
+
+	if rewrite {
+		n, err := w.ApplyReconcile(&s)
+		if err != nil {
+			fmt.Println("error reconciling wallet:", err)
+			return
+		}
+		err = w.WriteToFileWithBackup(walletFilename)
+		if err != nil {
+			fmt.Println("error writing wallet:", err)
+			return
+		}
+		fmt.Println("Accounts updated:", n)
+	}
 }
 
 func main() {
+	jsonOutput := flag.Bool("json", true, "render the data as JSON")
+	flag.BoolVar(&renderOptions.ShowSecrets, "show-secrets", false, "render private keys instead of redacting them")
+	reconcile := flag.Bool("reconcile", false, "compare the wallet cached values against the summary")
+	rewrite := flag.Bool("rewrite", false, "with -reconcile, write the summary values into the wallet")
+	fee := flag.String("fee", "", "print the protocol fee of an order moving this amount of Noso, e.g. 12.5")
+	root := flag.String("datadir", "", "node installation folder holding NOSODATA, instead of the test data")
+	flag.Parse()
+
+	if *root != "" {
+		dataDir = legacy.NewLegacyDataDir(*root)
+		for _, f := range dataDir.Missing() {
+			fmt.Fprintln(os.Stderr, "missing:", f)
+		}
+	}
+
+	if *fee != "" {
+		displayFee(*fee)
+		return
+	}
+
+	if *reconcile {
+		displayReconcile(*jsonOutput, *rewrite)
+		return
+	}
+
+	displayPSO(*jsonOutput)
+
+	displayGVT(*jsonOutput)
+
+	displaySummary(*jsonOutput)
+
+	displayWallet(*jsonOutput)
 
-	displayPSO(true)
+	displayBlock(*jsonOutput)
 
-	displayGVT(true)
+	displayHeaders(*jsonOutput)
 
-	displaySummary(true)
+	displayMasternodes(*jsonOutput)
 
-	displayWallet(true)
+	displayConfig(*jsonOutput)
 
-	displayBlock(true)
+	displayConsensus(*jsonOutput)
 }
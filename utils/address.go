@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+)
+
+const (
+	cCoinChar    = "N"
+	cB58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	cMinAddrLen  = 21
+	cMaxAddrLen  = 31 // 1 prefix, at most 28 hash and 2 checksum digits
+
+	cAliasChars     = "1234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ@*+-_:"
+	cMinAliasLength = 5
+	cMaxAliasLength = 40
+)
+
+// IsValidAddress checks the prefix and checksum of a Noso hash address
+func IsValidAddress(address string) bool {
+	if len(address) < cMinAddrLen || !strings.HasPrefix(address, cCoinChar) {
+		return false
+	}
+
+	// The checksum is at most 2 base58 digits, same as the Pascal node
+	hash := address[1 : len(address)-2]
+	if !IsValidB58(hash) {
+		return false
+	}
+
+	return cCoinChar+hash+B58Checksum(hash) == address
+}
+
+// LooksLikeAddress reports whether s has the shape of a hash address: the
+// prefix, an address length and base58 digits, whatever its checksum
+func LooksLikeAddress(s string) bool {
+	return len(s) >= cMinAddrLen && len(s) <= cMaxAddrLen &&
+		strings.HasPrefix(s, cCoinChar) && IsValidB58(s[1:])
+}
+
+// IsValidB58 checks that every character belongs to the base58 alphabet
+func IsValidB58(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(cB58Alphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// B58Checksum returns the base58 encoded sum of the digits of a base58 string
+func B58Checksum(s string) string {
+	var sum int64
+	for _, c := range s {
+		sum += int64(strings.IndexRune(cB58Alphabet, c))
+	}
+	return EncodeB58(big.NewInt(sum))
+}
+
+// EncodeB58 encodes a number using the base58 alphabet
+func EncodeB58(n *big.Int) string {
+	return encodeBase(n, cB58Alphabet)
+}
+
+// encodeBase encodes a number with the digits of the given alphabet
+func encodeBase(n *big.Int, alphabet string) string {
+	if n.Sign() == 0 {
+		return alphabet[:1]
+	}
+
+	var out []byte
+	b := big.NewInt(int64(len(alphabet)))
+	rest := new(big.Int).Set(n)
+	mod := new(big.Int)
+	for rest.Sign() > 0 {
+		rest.DivMod(rest, b, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+
+	// Reverse the digits
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// IsValidAlias checks the length and characters of a custom alias. Strings
+// shaped like an address are not aliases, so a mistyped address is rejected
+// rather than taken for an alias.
+func IsValidAlias(alias string) bool {
+	if len(alias) < cMinAliasLength || len(alias) > cMaxAliasLength {
+		return false
+	}
+	for _, c := range alias {
+		if !strings.ContainsRune(cAliasChars, c) {
+			return false
+		}
+	}
+	return !LooksLikeAddress(alias)
+}
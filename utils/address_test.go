@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestAddressAndAlias(t *testing.T) {
+	const address = "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9"
+	typo := "N2BrcTtmpoGsqCYWGx1fDsNSuyLNNH9" // One digit changed
+
+	tests := []struct {
+		s       string
+		address bool
+		alias   bool
+	}{
+		{address, true, false},
+		{typo, false, false},
+		{"payouts", false, true},
+		{"Nosopay", false, true}, // Too short for an address
+		{"pay", false, false},
+		{"pay outs", false, false},
+	}
+	for _, tt := range tests {
+		if got := IsValidAddress(tt.s); got != tt.address {
+			t.Errorf("IsValidAddress(%q): got %v", tt.s, got)
+		}
+		if got := IsValidAlias(tt.s); got != tt.alias {
+			t.Errorf("IsValidAlias(%q): got %v", tt.s, got)
+		}
+	}
+	if !LooksLikeAddress(typo) {
+		t.Errorf("LooksLikeAddress(%q): got false", typo)
+	}
+}
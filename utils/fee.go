@@ -0,0 +1,15 @@
+package utils
+
+const (
+	cFeeDivisor int64 = 10000 // Fee is 0.01% of the amount
+	cMinimumFee int64 = 10
+)
+
+// GetFee returns the protocol fee for transferring an amount
+func GetFee(amount int64) int64 {
+	fee := amount / cFeeDivisor
+	if fee < cMinimumFee {
+		fee = cMinimumFee
+	}
+	return fee
+}
@@ -1,9 +1,71 @@
 package utils
 
-import "os"
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
 
 // Check if the file exists
 func FileExists(filename string) bool {
 	_, err := os.Stat(filename)
 	return !os.IsNotExist(err)
 }
+
+// WriteFileAtomic writes a file through a temporary file in the same folder,
+// synced and renamed over the target, so a crash never leaves it half written
+func WriteFileAtomic(filename string, perm os.FileMode, write func(w io.Writer) error) error {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	file, err := os.CreateTemp(dir, base+".tmp*")
+	if err != nil {
+		return fmt.Errorf("cannot create file: %s", err)
+	}
+	tmp := file.Name()
+	defer os.Remove(tmp) // No-op once renamed
+
+	err = file.Chmod(perm)
+	if err == nil {
+		err = write(file)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp, filename)
+	if err != nil {
+		return fmt.Errorf("cannot replace file: %s", err)
+	}
+
+	// Persist the rename
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// BackupFile copies a file to the same name with a .bak extension, readable
+// by the owner only, and returns the backup name
+func BackupFile(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("cannot open file: %s", err)
+	}
+	backup := filename + ".bak"
+	err = WriteFileAtomic(backup, 0600, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	return backup, err
+}
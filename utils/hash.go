@@ -0,0 +1,22 @@
+package utils
+
+import "math/big"
+
+const (
+	cB36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	cTransferHashPrefix = "tR"
+	cOrderHashPrefix    = "OR"
+)
+
+// TransferHash returns the transfer ID for the text describing an order line
+func TransferHash(text string) string {
+	hash := HexToB58(HashSHA256String(text))
+	return cTransferHashPrefix + hash + B58Checksum(hash)
+}
+
+// OrderHash returns the order ID for the text describing an order
+func OrderHash(text string) string {
+	n, _ := new(big.Int).SetString(HashSHA256String(text), 16)
+	return cOrderHashPrefix + encodeBase(n, cB36Alphabet)
+}
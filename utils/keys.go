@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	cPrivateKeySize = 32
+	cB64Alphabet    = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+)
+
+// NewKeyPair generates a new secp256k1 key pair and returns the base64
+// private and public keys
+func NewKeyPair() (string, string, error) {
+	priv, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return "", "", err
+	}
+	privateKey := base64.StdEncoding.EncodeToString(priv.Serialize())
+	publicKey := base64.StdEncoding.EncodeToString(priv.PubKey().SerializeUncompressed())
+	return privateKey, publicKey, nil
+}
+
+// AddressFromPublicKey derives the Noso hash address of a base64 public key
+func AddressFromPublicKey(publicKey string) string {
+	shaHash := HashSHA256String(publicKey)
+
+	md160 := ripemd160.New()
+	md160.Write([]byte(shaHash))
+	hash := strings.ToUpper(fmt.Sprintf("%x", md160.Sum(nil)))
+
+	hash58 := HexToB58(hash)
+	return cCoinChar + hash58 + B58Checksum(hash58)
+}
+
+// PublicKeyFromPrivateKey derives the base64 public key of a base64 private key
+func PublicKeyFromPrivateKey(privateKey string) (string, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(priv.PubKey().SerializeUncompressed()), nil
+}
+
+// ValidatePublicKey checks that a base64 public key is a point on the curve
+func ValidatePublicKey(publicKey string) error {
+	_, err := parsePublicKey(publicKey)
+	return err
+}
+
+// SignMessage signs a message the way the Pascal wallet's GetStringSigned
+// does: the message text is decoded as base64 and its SHA-1 is signed
+// (SHA-1withECDSA). It returns the base64 DER signature.
+func SignMessage(message, privateKey string) (string, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	hash := sha1.Sum(decodeMessage(message))
+	sig := ecdsa.Sign(priv, hash[:])
+	return base64.StdEncoding.EncodeToString(sig.Serialize()), nil
+}
+
+// VerifyMessage checks a base64 DER signature made by SignMessage against a
+// base64 public key
+func VerifyMessage(message, signature, publicKey string) bool {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	der, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	sig, err := ecdsa.ParseDERSignature(der)
+	if err != nil {
+		return false
+	}
+	hash := sha1.Sum(decodeMessage(message))
+	return sig.Verify(hash[:], pub)
+}
+
+// decodeMessage decodes a signed message as base64 the lenient way the
+// Pascal wallet does: characters outside the alphabet are skipped, decoding
+// stops at padding and a trailing partial group yields its whole bytes
+func decodeMessage(message string) []byte {
+	var sb strings.Builder
+	for _, c := range message {
+		if c == '=' {
+			break
+		}
+		if strings.ContainsRune(cB64Alphabet, c) {
+			sb.WriteRune(c)
+		}
+	}
+	clean := sb.String()
+	if len(clean)%4 == 1 {
+		clean = clean[:len(clean)-1]
+	}
+	data, _ := base64.RawStdEncoding.DecodeString(clean)
+	return data
+}
+
+// HashSHA256String returns the uppercase hexadecimal SHA256 of a string
+func HashSHA256String(s string) string {
+	return strings.ToUpper(fmt.Sprintf("%x", sha256.Sum256([]byte(s))))
+}
+
+// HexToB58 converts a hexadecimal number to base58
+func HexToB58(hex string) string {
+	n, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		return ""
+	}
+	return EncodeB58(n)
+}
+
+// parsePrivateKey decodes a base64 private key
+func parsePrivateKey(privateKey string) (*secp256k1.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("private key is not base64: %s", err)
+	}
+	if len(raw) != cPrivateKeySize {
+		return nil, fmt.Errorf("private key is %d bytes, expected %d", len(raw), cPrivateKeySize)
+	}
+	priv := secp256k1.PrivKeyFromBytes(raw)
+	if priv.Key.IsZero() {
+		return nil, errors.New("private key is zero")
+	}
+	return priv, nil
+}
+
+// parsePublicKey decodes a base64 public key
+func parsePublicKey(publicKey string) (*secp256k1.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("public key is not base64: %s", err)
+	}
+	pub, err := secp256k1.ParsePubKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %s", err)
+	}
+	return pub, nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+func TestDecodeMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"QUJD", "ABC"},
+		{"QUJDRA", "ABCD"},  // Partial group of two characters
+		{"QUJDRA=", "ABCD"}, // Padding
+		{"QUJDR", "ABC"},    // A single trailing character carries no byte
+		{"QU JD!", "ABC"},   // Characters outside the alphabet are skipped
+	}
+	for _, tt := range tests {
+		if got := string(decodeMessage(tt.message)); got != tt.want {
+			t.Errorf("decodeMessage(%q): got %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	privateKey, publicKey, err := NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := "1700000000N2BrcTtmpoGsqCYWGx1fDsNSuyLNNG9N4DwRSTCFdNNV7JXuNfNYrbjipVauF6100000010001"
+
+	signature, err := SignMessage(message, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyMessage(message, signature, publicKey) {
+		t.Fatal("signature does not verify")
+	}
+	if VerifyMessage(strings.Replace(message, "1000", "2000", 1), signature, publicKey) {
+		t.Error("signature verifies for another message")
+	}
+
+	// The signed digest is the SHA-1 of the decoded message
+	der, _ := base64.StdEncoding.DecodeString(signature)
+	sig, err := ecdsa.ParseDERSignature(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := parsePublicKey(publicKey)
+	digest := sha1.Sum(decodeMessage(message))
+	if !sig.Verify(digest[:], pub) {
+		t.Error("signature is not over the SHA-1 of the decoded message")
+	}
+	raw := sha256.Sum256([]byte(message))
+	if sig.Verify(raw[:], pub) {
+		t.Error("signature is over the SHA256 of the raw message")
+	}
+}
+
+func TestVerifyMessageHighS(t *testing.T) {
+	privateKey, publicKey, err := NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := "QUJD"
+	signature, err := SignMessage(message, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Signers other than RFC6979 ones may produce the high S form
+	der, _ := base64.StdEncoding.DecodeString(signature)
+	var rs struct{ R, S *big.Int }
+	_, err = asn1.Unmarshal(der, &rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.S.Sub(secp256k1.S256().N, rs.S)
+	der, err = asn1.Marshal(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !VerifyMessage(message, base64.StdEncoding.EncodeToString(der), publicKey) {
+		t.Error("high S signature does not verify")
+	}
+}
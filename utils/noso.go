@@ -1,8 +1,55 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
+const (
+	cNosoDecimals       = 8
+	cNosoUnit     int64 = 100000000 // Smallest units in one Noso
+)
+
+// ToNoso formats an amount of smallest units as Noso, without float rounding
 func ToNoso(n int64) string {
-	var noso = float32(n) / 10e7
-	return fmt.Sprintf("%.8f Noso", noso)
+	sign := ""
+	u := uint64(n)
+	if n < 0 {
+		sign = "-"
+		u = -u
+	}
+	unit := uint64(cNosoUnit)
+	return fmt.Sprintf("%s%d.%08d Noso", sign, u/unit, u%unit)
+}
+
+// ParseNoso reads a decimal amount of Noso, such as 12.5, as smallest units
+func ParseNoso(s string) (int64, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "Noso"))
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid amount '%s'", s)
+	}
+	if len(fraction) > cNosoDecimals {
+		return 0, fmt.Errorf("amount '%s' has more than %d decimals", s, cNosoDecimals)
+	}
+	if strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("invalid amount '%s'", s)
+	}
+
+	var n, f int64
+	var err error
+	if whole != "" {
+		n, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil || n > (1<<63-1)/cNosoUnit-1 {
+			return 0, fmt.Errorf("invalid amount '%s'", s)
+		}
+	}
+	if fraction != "" {
+		f, err = strconv.ParseInt(fraction+strings.Repeat("0", cNosoDecimals-len(fraction)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount '%s'", s)
+		}
+	}
+	return n*cNosoUnit + f, nil
 }
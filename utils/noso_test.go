@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestToNoso(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0.00000000 Noso"},
+		{10, "0.00000010 Noso"},
+		{123456789012345678, "1234567890.12345678 Noso"},
+		{-150000000, "-1.50000000 Noso"},
+		{-1 << 63, "-92233720368.54775808 Noso"},
+	}
+	for _, tt := range tests {
+		if got := ToNoso(tt.n); got != tt.want {
+			t.Errorf("ToNoso(%d): got %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseNoso(t *testing.T) {
+	n, err := ParseNoso("12.5 Noso")
+	if err != nil || n != 1250000000 {
+		t.Errorf("got %d, %v", n, err)
+	}
+	for _, s := range []string{"", ".", "1.123456789", "-1", "abc"} {
+		if _, err := ParseNoso(s); err == nil {
+			t.Errorf("ParseNoso(%q): expected an error", s)
+		}
+	}
+}